@@ -0,0 +1,210 @@
+package allone
+
+import (
+	"fmt"
+	"hash/maphash"
+	"sync"
+)
+
+// MinMaxCounter defines the counter methods shared by AllOneMinMax and its concurrent variants.
+type MinMaxCounter[K comparable] interface {
+	Inc(K)
+	Dec(K)
+	Add(K, int64)
+	Count(K) int64
+	Len() int
+	GetMaxKey() (K, bool)
+	GetMinKey() (K, bool)
+}
+
+var (
+	_ MinMaxCounter[string] = &AllOneMinMax[string]{}
+	_ MinMaxCounter[string] = &SyncAllOneMinMax[string]{}
+	_ MinMaxCounter[string] = &ShardedAllOneMinMax[string]{}
+)
+
+// -------------- SyncAllOneMinMax --------------
+
+// SyncAllOneMinMax is an AllOneMinMax guarded by a single lock. All the methods are
+// linearizable, so the results are exact, but all the writers contend on the same lock.
+type SyncAllOneMinMax[K comparable] struct {
+	mu sync.RWMutex
+	a  *AllOneMinMax[K]
+}
+
+func NewSyncAllOneMinMax[K comparable](opts Options) *SyncAllOneMinMax[K] {
+	return &SyncAllOneMinMax[K]{
+		a: NewAllOneMinMax[K](opts),
+	}
+}
+
+func (s *SyncAllOneMinMax[K]) Inc(key K) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.a.Inc(key)
+}
+
+func (s *SyncAllOneMinMax[K]) Dec(key K) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.a.Dec(key)
+}
+
+func (s *SyncAllOneMinMax[K]) Add(key K, delta int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.a.Add(key, delta)
+}
+
+func (s *SyncAllOneMinMax[K]) Count(key K) int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.a.Count(key)
+}
+
+func (s *SyncAllOneMinMax[K]) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.a.Len()
+}
+
+func (s *SyncAllOneMinMax[K]) GetMaxKey() (K, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.a.GetMaxKey()
+}
+
+func (s *SyncAllOneMinMax[K]) GetMinKey() (K, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.a.GetMinKey()
+}
+
+// -------------- ShardedAllOneMinMax --------------
+
+type shard[K comparable] struct {
+	mu sync.RWMutex
+	a  *AllOneMinMax[K]
+}
+
+// ShardedAllOneMinMax partitions the keys into shards by hash, and each shard is an
+// AllOneMinMax guarded by its own lock, so writers on different shards do not contend.
+//
+// Inc, Dec, Add and Count only touch the shard of the key and are linearizable per key.
+// GetMaxKey and GetMinKey scan all the shards one by one rather than keeping a merged
+// extreme, so each call costs O(shards) lock acquisitions. The result is exact when there are
+// no concurrent writers; otherwise it is the extreme among the per-shard results, each of
+// which was exact when its shard was visited. Len is a sum of per-shard snapshots as well.
+type ShardedAllOneMinMax[K comparable] struct {
+	shards []shard[K]
+	hash   func(K) uint64
+}
+
+// NewShardedAllOneMinMax returns a ShardedAllOneMinMax with n shards, where `hash` maps each
+// key to its shard. A nil hash falls back to StringHasher for string keys, and to hashing
+// the fmt.Sprint form of the key otherwise, which works for any K but is slower.
+func NewShardedAllOneMinMax[K comparable](n int, hash func(K) uint64, opts Options) *ShardedAllOneMinMax[K] {
+	if n <= 0 {
+		n = 1
+	}
+	if hash == nil {
+		hash = defaultHasher[K]()
+	}
+	s := &ShardedAllOneMinMax[K]{
+		shards: make([]shard[K], n),
+		hash:   hash,
+	}
+	for i := range s.shards {
+		s.shards[i].a = NewAllOneMinMax[K](opts)
+	}
+	return s
+}
+
+// StringHasher returns a hash function for string keys, which can be used by ShardedAllOneMinMax.
+func StringHasher() func(string) uint64 {
+	seed := maphash.MakeSeed()
+	return func(key string) uint64 {
+		var h maphash.Hash
+		h.SetSeed(seed)
+		h.WriteString(key)
+		return h.Sum64()
+	}
+}
+
+func defaultHasher[K comparable]() func(K) uint64 {
+	if hash, ok := any(StringHasher()).(func(K) uint64); ok {
+		return hash
+	}
+	hash := StringHasher()
+	return func(key K) uint64 {
+		return hash(fmt.Sprint(key))
+	}
+}
+
+func (s *ShardedAllOneMinMax[K]) shard(key K) *shard[K] {
+	return &s.shards[s.hash(key)%uint64(len(s.shards))]
+}
+
+func (s *ShardedAllOneMinMax[K]) Inc(key K) {
+	s.Add(key, 1)
+}
+
+func (s *ShardedAllOneMinMax[K]) Dec(key K) {
+	s.Add(key, -1)
+}
+
+func (s *ShardedAllOneMinMax[K]) Add(key K, delta int64) {
+	sh := s.shard(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	sh.a.Add(key, delta)
+}
+
+func (s *ShardedAllOneMinMax[K]) Count(key K) int64 {
+	sh := s.shard(key)
+	sh.mu.RLock()
+	defer sh.mu.RUnlock()
+	return sh.a.Count(key)
+}
+
+func (s *ShardedAllOneMinMax[K]) Len() int {
+	ret := 0
+	for i := range s.shards {
+		sh := &s.shards[i]
+		sh.mu.RLock()
+		ret += sh.a.Len()
+		sh.mu.RUnlock()
+	}
+	return ret
+}
+
+func (s *ShardedAllOneMinMax[K]) GetMaxKey() (K, bool) {
+	return s.extreme(true)
+}
+
+func (s *ShardedAllOneMinMax[K]) GetMinKey() (K, bool) {
+	return s.extreme(false)
+}
+
+func (s *ShardedAllOneMinMax[K]) extreme(greatest bool) (K, bool) {
+	var ret K
+	var retCount int64
+	found := false
+	for i := range s.shards {
+		sh := &s.shards[i]
+		sh.mu.RLock()
+		var key K
+		var ok bool
+		if greatest {
+			key, ok = sh.a.GetMaxKey()
+		} else {
+			key, ok = sh.a.GetMinKey()
+		}
+		count := sh.a.Count(key)
+		sh.mu.RUnlock()
+		if ok && (!found || greatest && count > retCount || !greatest && count < retCount) {
+			ret, retCount, found = key, count, true
+		}
+	}
+	return ret, found
+}
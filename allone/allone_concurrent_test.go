@@ -0,0 +1,79 @@
+package allone
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestConcurrentAllOneMinMax(t *testing.T) {
+	counters := map[string]MinMaxCounter[string]{
+		"sync":    NewSyncAllOneMinMax[string](Options{}),
+		"sharded": NewShardedAllOneMinMax[string](8, StringHasher(), Options{}),
+	}
+	for name, c := range counters {
+		t.Run(name, func(t *testing.T) {
+			if _, ok := c.GetMaxKey(); ok {
+				t.Errorf("GetMaxKey on empty counter should fail")
+			}
+
+			// Each worker increases key i by i+1 and then decreases it once, so the count of
+			// key i is i*workers in total.
+			const workers, keys = 8, 20
+			var wg sync.WaitGroup
+			for w := 0; w < workers; w++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for i := 0; i < keys; i++ {
+						for j := 0; j <= i; j++ {
+							c.Inc(strconv.Itoa(i))
+						}
+						c.Dec(strconv.Itoa(i))
+					}
+				}()
+			}
+			wg.Wait()
+
+			if c.Len() != keys-1 {
+				t.Errorf("Len get %v, want %v", c.Len(), keys-1)
+			}
+			for i := 0; i < keys; i++ {
+				if got, want := c.Count(strconv.Itoa(i)), int64(i*workers); got != want {
+					t.Errorf("Count(%v) get %v, want %v", i, got, want)
+				}
+			}
+			if key, _ := c.GetMaxKey(); key != strconv.Itoa(keys-1) {
+				t.Errorf("GetMaxKey get %v, want %v", key, keys-1)
+			}
+			if key, _ := c.GetMinKey(); key != "1" {
+				t.Errorf("GetMinKey get %v, want %v", key, 1)
+			}
+
+			c.Add("1", 1000)
+			if key, _ := c.GetMaxKey(); key != "1" {
+				t.Errorf("GetMaxKey get %v, want %v", key, 1)
+			}
+		})
+	}
+}
+
+func TestShardedAllOneMinMax_NilHash(t *testing.T) {
+	s := NewShardedAllOneMinMax[string](4, nil, Options{})
+	s.Add("a", 2)
+	s.Inc("b")
+	if key, _ := s.GetMaxKey(); key != "a" {
+		t.Errorf("GetMaxKey get %v, want %v", key, "a")
+	}
+
+	n := NewShardedAllOneMinMax[int](4, nil, Options{})
+	for i := 1; i <= 10; i++ {
+		n.Add(i, int64(i))
+	}
+	if n.Len() != 10 || n.Count(7) != 7 {
+		t.Errorf("Len get %v, Count(7) get %v, want 10 and 7", n.Len(), n.Count(7))
+	}
+	if key, _ := n.GetMinKey(); key != 1 {
+		t.Errorf("GetMinKey get %v, want %v", key, 1)
+	}
+}
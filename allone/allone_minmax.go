@@ -5,135 +5,281 @@ import (
 	"math"
 )
 
-// Node define the node that
-type Node struct {
-	val int64
-	set map[any]struct{}
+// TieBreak defines which key will be picked among the keys with the same count.
+type TieBreak int
+
+const (
+	// TieBreakEarliest picks the key that reached the count first.
+	TieBreakEarliest TieBreak = iota
+	// TieBreakLatest picks the key that reached the count last.
+	TieBreakLatest
+)
+
+// Node define the bucket that holds all the keys with the same count. Keys are kept in the
+// order in which they arrived at the bucket.
+type Node[K comparable] struct {
+	val  int64
+	set  map[K]*list.Element
+	keys *list.List
 }
 
-func NewNode(val int64) *Node {
-	return &Node{
-		val: val,
-		set: make(map[any]struct{}),
+func NewNode[K comparable](val int64) *Node[K] {
+	return &Node[K]{
+		val:  val,
+		set:  make(map[K]*list.Element),
+		keys: list.New(),
 	}
 }
 
-func (n *Node) Erase(key any) {
-	delete(n.set, key)
+func (n *Node[K]) Erase(key K) {
+	if e, ok := n.set[key]; ok {
+		n.keys.Remove(e)
+		delete(n.set, key)
+	}
 }
 
-func (n *Node) Insert(key any) {
-	n.set[key] = struct{}{}
+func (n *Node[K]) Insert(key K) {
+	if _, ok := n.set[key]; !ok {
+		n.set[key] = n.keys.PushBack(key)
+	}
 }
 
-func (n *Node) Has(key any) bool {
+func (n *Node[K]) Has(key K) bool {
 	_, ok := n.set[key]
 	return ok
 }
 
-func (n *Node) Size() int64 {
+func (n *Node[K]) Size() int64 {
 	return int64(len(n.set))
 }
 
-func (n *Node) PickOneKey() any {
-	for k := range n.set {
-		return k
+func (n *Node[K]) Val() int64 {
+	return n.val
+}
+
+// PickOneKey returns a key according to the TieBreak.
+func (n *Node[K]) PickOneKey(tieBreak TieBreak) (K, bool) {
+	e := n.keys.Front()
+	if tieBreak == TieBreakLatest {
+		e = n.keys.Back()
 	}
-	return ""
+	if e == nil {
+		var zero K
+		return zero, false
+	}
+	return e.Value.(K), true
+}
+
+// Keys returns all the keys in arrival order.
+func (n *Node[K]) Keys() []K {
+	ret := make([]K, 0, n.keys.Len())
+	for e := n.keys.Front(); e != nil; e = e.Next() {
+		ret = append(ret, e.Value.(K))
+	}
+	return ret
 }
 
 // -------------- AllOneMinMax --------------
-type AllOneMinMax struct {
-	data *list.List
-	hash map[any]*list.Element
+
+// Options defines the optional behaviors of AllOneMinMax.
+type Options struct {
+	// TieBreak decides which key will be picked among the keys with the same count.
+	TieBreak TieBreak
+	// KeepNonPositive keeps the keys whose counts drop to zero or below, instead of removing them.
+	KeepNonPositive bool
 }
 
-func AllOneMinMaxConstructor() AllOneMinMax {
+type AllOneMinMax[K comparable] struct {
+	data  *list.List
+	hash  map[K]*list.Element
+	index bucketIndex
+	opts  Options
+}
+
+func AllOneMinMaxConstructor[K comparable]() AllOneMinMax[K] {
+	return *NewAllOneMinMax[K](Options{})
+}
+
+func NewAllOneMinMax[K comparable](opts Options) *AllOneMinMax[K] {
 	data := list.New()
-	data.PushFront(NewNode(math.MinInt64))
-	data.PushBack(NewNode(math.MaxInt64))
-	return AllOneMinMax{
+	data.PushFront(NewNode[K](math.MinInt64))
+	data.PushBack(NewNode[K](math.MaxInt64))
+	return &AllOneMinMax[K]{
 		data: data,
-		hash: make(map[any]*list.Element, 0),
+		hash: make(map[K]*list.Element, 0),
+		index: newBucketIndex(data, func(ele *list.Element) int64 {
+			return ele.Value.(*Node[K]).val
+		}),
+		opts: opts,
+	}
+}
+
+// lessEqual reports whether the bucket is before or at the count val. The sentinels are
+// identified by element rather than by count, since real buckets may share their counts.
+func (this *AllOneMinMax[K]) lessEqual(ele *list.Element, val int64) bool {
+	if ele == this.data.Front() {
+		return true
 	}
+	return ele != this.data.Back() && ele.Value.(*Node[K]).val <= val
 }
 
-func (this *AllOneMinMax) add_to_right(ele *list.Element, key any, val int64) *list.Element {
-	if ele.Next().Value.(*Node).val == val {
-		ele.Next().Value.(*Node).Insert(key)
-	} else {
-		t := NewNode(val)
-		t.Insert(key)
-		this.data.InsertAfter(t, ele)
+// locate returns the real bucket with the greatest count that is not greater than val, or
+// the front sentinel if there is none. It checks the neighbours of `from` first, so moving a
+// key to an adjacent count costs O(1) and leaves the bucket index alone.
+func (this *AllOneMinMax[K]) locate(from *list.Element, val int64) *list.Element {
+	if from == nil {
+		from = this.data.Front()
 	}
-	return ele.Next()
+	if this.lessEqual(from, val) {
+		if next := from.Next(); this.lessEqual(next, val) {
+			if next.Value.(*Node[K]).val == val {
+				return next
+			}
+		} else {
+			return from
+		}
+	} else if prev := from.Prev(); this.lessEqual(prev, val) {
+		return prev
+	}
+	if ele := this.index.floor(val); ele != nil {
+		return ele
+	}
+	return this.data.Front()
 }
 
-func (this *AllOneMinMax) add_to_left(ele *list.Element, key any, val int64) *list.Element {
-	if ele.Prev().Value.(*Node).val == val {
-		ele.Prev().Value.(*Node).Insert(key)
-	} else {
-		t := NewNode(val)
-		t.Insert(key)
-		this.data.InsertBefore(t, ele)
+// insert puts the key into the bucket of val, which will be created after `floor` if needed.
+func (this *AllOneMinMax[K]) insert(floor *list.Element, key K, val int64) *list.Element {
+	if floor != this.data.Front() && floor.Value.(*Node[K]).val == val {
+		floor.Value.(*Node[K]).Insert(key)
+		return floor
 	}
-	return ele.Prev()
+	t := NewNode[K](val)
+	t.Insert(key)
+	ele := this.data.InsertAfter(t, floor)
+	this.index.insert(val, ele)
+	return ele
 }
 
-func (this *AllOneMinMax) remove(node *list.Element) {
+// remove unlinks the empty real bucket. The sentinels are never removed.
+func (this *AllOneMinMax[K]) remove(node *list.Element) {
+	if node == this.data.Front() || node == this.data.Back() {
+		return
+	}
+	this.index.erase(node.Value.(*Node[K]).val)
 	this.data.Remove(node)
 }
 
-func (this *AllOneMinMax) Inc(key any) {
-	if _, ok := this.hash[key]; !ok {
-		this.hash[key] = this.add_to_right(this.data.Front(), key, 1)
-	} else {
-		ele := this.hash[key]
-		node := ele.Value.(*Node)
+func (this *AllOneMinMax[K]) Inc(key K) {
+	this.Add(key, 1)
+}
+
+func (this *AllOneMinMax[K]) Dec(key K) {
+	this.Add(key, -1)
+}
+
+// Add adds delta to the count of the key. A missing key is treated as count 0. The count
+// saturates at math.MaxInt64 and math.MinInt64 instead of overflowing.
+func (this *AllOneMinMax[K]) Add(key K, delta int64) {
+	count := this.Count(key)
+	switch {
+	case delta > 0 && count > math.MaxInt64-delta:
+		count = math.MaxInt64
+	case delta < 0 && count < math.MinInt64-delta:
+		count = math.MinInt64
+	default:
+		count += delta
+	}
+	this.Set(key, count)
+}
+
+// Set sets the count of the key. Unless Options.KeepNonPositive is set, the key will be
+// removed if count is not positive.
+// It costs O(1) amortized if the key stays in or moves to an adjacent bucket. Otherwise the
+// bucket index is brought up to date first, which costs O(log n) expected for each bucket
+// created or removed since the last far move and at most O(n) in total, and the lookup
+// costs O(log n) expected, where n is the number of distinct counts.
+func (this *AllOneMinMax[K]) Set(key K, count int64) {
+	if count <= 0 && !this.opts.KeepNonPositive {
+		this.Remove(key)
+		return
+	}
+	ele, ok := this.hash[key]
+	if ok && ele.Value.(*Node[K]).val == count {
+		return
+	}
+	this.hash[key] = this.insert(this.locate(ele, count), key, count)
+	if ok {
+		node := ele.Value.(*Node[K])
 		node.Erase(key)
-		this.hash[key] = this.add_to_right(ele, key, node.val+1)
 		if node.Size() == 0 {
 			this.remove(ele)
 		}
 	}
 }
 
-func (this *AllOneMinMax) Dec(key any) {
-	if _, ok := this.hash[key]; !ok {
+// Remove removes the key regardless of its count.
+func (this *AllOneMinMax[K]) Remove(key K) {
+	ele, ok := this.hash[key]
+	if !ok {
 		return
 	}
-	ele := this.hash[key]
-	node := ele.Value.(*Node)
+	node := ele.Value.(*Node[K])
 	node.Erase(key)
-	if node.val > 1 {
-		this.hash[key] = this.add_to_left(ele, key, node.val-1)
-	} else {
-		delete(this.hash, key)
-	}
+	delete(this.hash, key)
 	if node.Size() == 0 {
 		this.remove(ele)
 	}
 }
 
-func (this *AllOneMinMax) GetMaxKey() any {
+// Count returns the count of the key, or 0 if the key does not exist.
+func (this *AllOneMinMax[K]) Count(key K) int64 {
+	if ele, ok := this.hash[key]; ok {
+		return ele.Value.(*Node[K]).val
+	}
+	return 0
+}
+
+func (this *AllOneMinMax[K]) Len() int {
+	return len(this.hash)
+}
+
+func (this *AllOneMinMax[K]) GetMaxKey() (K, bool) {
+	if len(this.hash) > 0 {
+		return this.data.Back().Prev().Value.(*Node[K]).PickOneKey(this.opts.TieBreak)
+	}
+	var zero K
+	return zero, false
+}
+
+func (this *AllOneMinMax[K]) GetMinKey() (K, bool) {
+	if len(this.hash) > 0 {
+		return this.data.Front().Next().Value.(*Node[K]).PickOneKey(this.opts.TieBreak)
+	}
+	var zero K
+	return zero, false
+}
+
+// GetMaxKeys returns all the keys tied at the max count in arrival order.
+func (this *AllOneMinMax[K]) GetMaxKeys() []K {
 	if len(this.hash) > 0 {
-		return this.data.Back().Prev().Value.(*Node).PickOneKey()
+		return this.data.Back().Prev().Value.(*Node[K]).Keys()
 	}
-	return ""
+	return nil
 }
 
-func (this *AllOneMinMax) GetMinKey() any {
+// GetMinKeys returns all the keys tied at the min count in arrival order.
+func (this *AllOneMinMax[K]) GetMinKeys() []K {
 	if len(this.hash) > 0 {
-		return this.data.Front().Next().Value.(*Node).PickOneKey()
+		return this.data.Front().Next().Value.(*Node[K]).Keys()
 	}
-	return ""
+	return nil
 }
 
 /**
  * Your AllOneMinMax object will be instantiated and called as such:
- * obj := Constructor();
+ * obj := AllOneMinMaxConstructor[string]();
  * obj.Inc(key);
  * obj.Dec(key);
- * param_3 := obj.GetMaxKey();
- * param_4 := obj.GetMinKey();
+ * param_3, ok := obj.GetMaxKey();
+ * param_4, ok := obj.GetMinKey();
  */
@@ -0,0 +1,99 @@
+package allone
+
+import "container/list"
+
+// Entry is a key and its count.
+type Entry[K comparable] struct {
+	Key   K
+	Count int64
+}
+
+// BucketRangeFunc visits the buckets in order, and stops the traversal if it returns false.
+type BucketRangeFunc[K comparable] func(count int64, keys []K) bool
+
+// keysInOrder returns the keys of the bucket, ordered by the TieBreak.
+func (this *AllOneMinMax[K]) keysInOrder(n *Node[K]) []K {
+	keys := n.Keys()
+	if this.opts.TieBreak == TieBreakLatest {
+		for i, j := 0, len(keys)-1; i < j; i, j = i+1, j-1 {
+			keys[i], keys[j] = keys[j], keys[i]
+		}
+	}
+	return keys
+}
+
+// collect walks the buckets from `ele` in the direction of `next` and returns up to k entries.
+// The keys of each bucket are walked in place, so it costs O(k) plus the buckets visited.
+func (this *AllOneMinMax[K]) collect(k int, ele *list.Element, next func(*list.Element) *list.Element) []Entry[K] {
+	if k > len(this.hash) {
+		k = len(this.hash)
+	}
+	if k <= 0 {
+		return nil
+	}
+	first, step := (*list.List).Front, (*list.Element).Next
+	if this.opts.TieBreak == TieBreakLatest {
+		first, step = (*list.List).Back, (*list.Element).Prev
+	}
+	ret := make([]Entry[K], 0, k)
+	for ; len(ret) < k; ele = next(ele) {
+		node := ele.Value.(*Node[K])
+		for e := first(node.keys); e != nil && len(ret) < k; e = step(e) {
+			ret = append(ret, Entry[K]{Key: e.Value.(K), Count: node.val})
+		}
+	}
+	return ret
+}
+
+// TopK returns up to k entries with the greatest counts, in descending order of count.
+func (this *AllOneMinMax[K]) TopK(k int) []Entry[K] {
+	return this.collect(k, this.data.Back().Prev(), (*list.Element).Prev)
+}
+
+// BottomK returns up to k entries with the smallest counts, in ascending order of count.
+func (this *AllOneMinMax[K]) BottomK(k int) []Entry[K] {
+	return this.collect(k, this.data.Front().Next(), (*list.Element).Next)
+}
+
+// KeysWithCount returns all the keys whose count is exactly c.
+func (this *AllOneMinMax[K]) KeysWithCount(c int64) []K {
+	ele := this.index.floor(c)
+	if ele == nil || ele.Value.(*Node[K]).val != c {
+		return nil
+	}
+	return this.keysInOrder(ele.Value.(*Node[K]))
+}
+
+// CountAtLeast returns the number of keys whose count is not less than c. It walks the
+// buckets from the max one, so it costs O(m) where m is the number of buckets counted.
+func (this *AllOneMinMax[K]) CountAtLeast(c int64) int {
+	ret := 0
+	for ele := this.data.Back().Prev(); ele != this.data.Front(); ele = ele.Prev() {
+		node := ele.Value.(*Node[K])
+		if node.val < c {
+			break
+		}
+		ret += int(node.Size())
+	}
+	return ret
+}
+
+// RangeBuckets traverses the buckets in ascending order of count.
+func (this *AllOneMinMax[K]) RangeBuckets(f BucketRangeFunc[K]) {
+	for ele := this.data.Front().Next(); ele != this.data.Back(); ele = ele.Next() {
+		node := ele.Value.(*Node[K])
+		if !f(node.val, this.keysInOrder(node)) {
+			return
+		}
+	}
+}
+
+// ReverseRangeBuckets traverses the buckets in descending order of count.
+func (this *AllOneMinMax[K]) ReverseRangeBuckets(f BucketRangeFunc[K]) {
+	for ele := this.data.Back().Prev(); ele != this.data.Front(); ele = ele.Prev() {
+		node := ele.Value.(*Node[K])
+		if !f(node.val, this.keysInOrder(node)) {
+			return
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package allone
+
+import (
+	"reflect"
+	"testing"
+)
+
+func newQueryTestingAllOne(tieBreak TieBreak) *AllOneMinMax[string] {
+	this := NewAllOneMinMax[string](Options{TieBreak: tieBreak})
+	this.Add("a", 5)
+	this.Add("b", 3)
+	this.Add("c", 5)
+	this.Add("d", 1)
+	this.Add("e", 3)
+	return this
+}
+
+func TestAllOneMinMax_TopKBottomK(t *testing.T) {
+	this := newQueryTestingAllOne(TieBreakEarliest)
+	tests := []struct {
+		name string
+		got  []Entry[string]
+		want []Entry[string]
+	}{
+		{
+			name: "top 3",
+			got:  this.TopK(3),
+			want: []Entry[string]{{"a", 5}, {"c", 5}, {"b", 3}},
+		},
+		{
+			name: "bottom 2",
+			got:  this.BottomK(2),
+			want: []Entry[string]{{"d", 1}, {"b", 3}},
+		},
+		{
+			name: "top all",
+			got:  this.TopK(10),
+			want: []Entry[string]{{"a", 5}, {"c", 5}, {"b", 3}, {"e", 3}, {"d", 1}},
+		},
+		{
+			name: "zero",
+			got:  this.BottomK(0),
+			want: nil,
+		},
+		{
+			name: "latest tie break",
+			got:  newQueryTestingAllOne(TieBreakLatest).TopK(3),
+			want: []Entry[string]{{"c", 5}, {"a", 5}, {"e", 3}},
+		},
+		{
+			name: "latest tie break within a bucket",
+			got:  newQueryTestingAllOne(TieBreakLatest).BottomK(2),
+			want: []Entry[string]{{"d", 1}, {"e", 3}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.got, tt.want) {
+				t.Errorf("get %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllOneMinMax_CountQueries(t *testing.T) {
+	this := newQueryTestingAllOne(TieBreakEarliest)
+	if got, want := this.KeysWithCount(3), []string{"b", "e"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("KeysWithCount get %v, want %v", got, want)
+	}
+	if got := this.KeysWithCount(4); got != nil {
+		t.Errorf("KeysWithCount get %v, want nil", got)
+	}
+	for c, want := range map[int64]int{0: 5, 1: 5, 2: 4, 3: 4, 5: 2, 6: 0} {
+		if got := this.CountAtLeast(c); got != want {
+			t.Errorf("CountAtLeast(%v) get %v, want %v", c, got, want)
+		}
+	}
+}
+
+func TestAllOneMinMax_RangeBuckets(t *testing.T) {
+	this := newQueryTestingAllOne(TieBreakEarliest)
+	var counts []int64
+	var keys [][]string
+	this.RangeBuckets(func(count int64, k []string) bool {
+		counts, keys = append(counts, count), append(keys, k)
+		return true
+	})
+	if want := []int64{1, 3, 5}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("RangeBuckets get counts %v, want %v", counts, want)
+	}
+	if want := [][]string{{"d"}, {"b", "e"}, {"a", "c"}}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("RangeBuckets get keys %v, want %v", keys, want)
+	}
+
+	counts = counts[:0]
+	this.ReverseRangeBuckets(func(count int64, _ []string) bool {
+		counts = append(counts, count)
+		return count > 3
+	})
+	if want := []int64{5, 3}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("ReverseRangeBuckets get counts %v, want %v", counts, want)
+	}
+}
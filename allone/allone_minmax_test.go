@@ -1,16 +1,20 @@
 package allone
 
 import (
+	"container/list"
+	"math"
+	"math/rand"
+	"reflect"
 	"testing"
 )
 
 /**
  * Your AllOneMinMax object will be instantiated and called as such:
- * obj := Constructor();
+ * obj := AllOneMinMaxConstructor[string]();
  * obj.Inc(key);
  * obj.Dec(key);
- * param_3 := obj.GetMaxKey();
- * param_4 := obj.GetMinKey();
+ * param_3, ok := obj.GetMaxKey();
+ * param_4, ok := obj.GetMinKey();
  */
 
 func TestAllOneMinMax(t *testing.T) {
@@ -34,7 +38,7 @@ func TestAllOneMinMax(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			this := AllOneMinMaxConstructor()
+			this := AllOneMinMaxConstructor[string]()
 			for i := range tt.args.op {
 				switch tt.args.op[i] {
 				case "inc":
@@ -42,11 +46,11 @@ func TestAllOneMinMax(t *testing.T) {
 				case "dec":
 					this.Dec(tt.args.in[i])
 				case "getMaxKey":
-					if key := this.GetMaxKey(); key != tt.want[i] {
+					if key, _ := this.GetMaxKey(); key != tt.want[i] {
 						t.Errorf("GetMaxKey get %v, want %v\n", key, tt.want[i])
 					}
 				case "getMinKey":
-					if key := this.GetMinKey(); key != tt.want[i] {
+					if key, _ := this.GetMinKey(); key != tt.want[i] {
 						t.Errorf("GetMinKey get %v, want %v\n", key, tt.want[i])
 					}
 				default:
@@ -57,3 +61,247 @@ func TestAllOneMinMax(t *testing.T) {
 		)
 	}
 }
+
+func TestAllOneMinMax_Generic(t *testing.T) {
+	this := AllOneMinMaxConstructor[string]()
+	if _, ok := this.GetMaxKey(); ok {
+		t.Errorf("GetMaxKey on empty AllOneMinMax should fail")
+	}
+	if _, ok := this.GetMinKey(); ok {
+		t.Errorf("GetMinKey on empty AllOneMinMax should fail")
+	}
+
+	// The empty string is a legitimate key.
+	this.Inc("")
+	if key, ok := this.GetMaxKey(); !ok || key != "" {
+		t.Errorf("GetMaxKey get %q, %v, want %q, %v", key, ok, "", true)
+	}
+	this.Dec("")
+	if this.Len() != 0 || this.Count("") != 0 {
+		t.Errorf("Len get %v, Count get %v, want empty", this.Len(), this.Count(""))
+	}
+
+	for _, key := range []string{"a", "b", "c", "a", "b", "c", "d"} {
+		this.Inc(key)
+	}
+	if got := this.Count("a"); got != 2 {
+		t.Errorf("Count get %v, want %v", got, 2)
+	}
+	if got := this.Count("x"); got != 0 {
+		t.Errorf("Count get %v, want %v", got, 0)
+	}
+	if got, want := this.GetMaxKeys(), []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetMaxKeys get %v, want %v", got, want)
+	}
+	if got, want := this.GetMinKeys(), []string{"d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetMinKeys get %v, want %v", got, want)
+	}
+}
+
+func TestAllOneMinMax_TieBreak(t *testing.T) {
+	tests := []struct {
+		name     string
+		tieBreak TieBreak
+		wantMax  int
+		wantMin  int
+	}{
+		{name: "earliest", tieBreak: TieBreakEarliest, wantMax: 2, wantMin: 1},
+		{name: "latest", tieBreak: TieBreakLatest, wantMax: 3, wantMin: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Repeat to make sure the result does not depend on map iteration.
+			for round := 0; round < 10; round++ {
+				this := NewAllOneMinMax[int](Options{TieBreak: tt.tieBreak})
+				for _, key := range []int{1, 2, 3, 4, 2, 3} {
+					this.Inc(key)
+				}
+				if key, _ := this.GetMaxKey(); key != tt.wantMax {
+					t.Fatalf("GetMaxKey get %v, want %v", key, tt.wantMax)
+				}
+				if key, _ := this.GetMinKey(); key != tt.wantMin {
+					t.Fatalf("GetMinKey get %v, want %v", key, tt.wantMin)
+				}
+			}
+		})
+	}
+}
+
+func TestAllOneMinMax_AddSet(t *testing.T) {
+	this := AllOneMinMaxConstructor[string]()
+	this.Add("a", 500)
+	this.Add("b", 3)
+	this.Set("c", 100)
+	this.Inc("b")
+	if key, _ := this.GetMaxKey(); key != "a" {
+		t.Errorf("GetMaxKey get %v, want %v", key, "a")
+	}
+	if key, _ := this.GetMinKey(); key != "b" {
+		t.Errorf("GetMinKey get %v, want %v", key, "b")
+	}
+
+	this.Add("a", -450)
+	this.Set("b", 50)
+	if got, want := this.GetMinKeys(), []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetMinKeys get %v, want %v", got, want)
+	}
+	if key, _ := this.GetMaxKey(); key != "c" {
+		t.Errorf("GetMaxKey get %v, want %v", key, "c")
+	}
+
+	// Counts that drop to zero or below remove the key by default.
+	this.Add("c", -200)
+	this.Add("x", -1)
+	this.Set("a", 0)
+	if this.Len() != 1 || this.Count("c") != 0 || this.Count("x") != 0 {
+		t.Errorf("Len get %v, want %v", this.Len(), 1)
+	}
+}
+
+func TestAllOneMinMax_KeepNonPositive(t *testing.T) {
+	this := NewAllOneMinMax[string](Options{KeepNonPositive: true})
+	this.Dec("a")
+	this.Add("b", -10)
+	this.Set("c", 0)
+	this.Inc("d")
+	if this.Len() != 4 {
+		t.Errorf("Len get %v, want %v", this.Len(), 4)
+	}
+	if key, _ := this.GetMinKey(); key != "b" {
+		t.Errorf("GetMinKey get %v, want %v", key, "b")
+	}
+	if key, _ := this.GetMaxKey(); key != "d" {
+		t.Errorf("GetMaxKey get %v, want %v", key, "d")
+	}
+	if got := this.Count("a"); got != -1 {
+		t.Errorf("Count get %v, want %v", got, -1)
+	}
+	this.Remove("b")
+	if key, _ := this.GetMinKey(); key != "a" {
+		t.Errorf("GetMinKey get %v, want %v", key, "a")
+	}
+}
+
+func TestAllOneMinMax_Extremes(t *testing.T) {
+	this := NewAllOneMinMax[string](Options{KeepNonPositive: true})
+	this.Set("c", 1)
+	this.Set("a", math.MaxInt64)
+	this.Set("d", math.MaxInt64)
+	if got, want := this.GetMaxKeys(), []string{"a", "d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetMaxKeys get %v, want %v", got, want)
+	}
+	// Counts saturate instead of overflowing.
+	this.Inc("a")
+	this.Add("c", math.MaxInt64)
+	if got, want := this.GetMaxKeys(), []string{"a", "d", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetMaxKeys get %v, want %v", got, want)
+	}
+	this.Remove("a")
+	this.Remove("d")
+	this.Remove("c")
+	this.Set("e", 5)
+	if key, ok := this.GetMaxKey(); !ok || key != "e" {
+		t.Errorf("GetMaxKey get %v, want %v", key, "e")
+	}
+
+	this.Set("x", math.MinInt64)
+	this.Add("y", math.MinInt64)
+	this.Dec("y")
+	if got, want := this.GetMinKeys(), []string{"x", "y"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetMinKeys get %v, want %v", got, want)
+	}
+	this.Remove("x")
+	this.Remove("y")
+	if got, want := this.GetMinKeys(), []string{"e"}; this.Len() != 1 || !reflect.DeepEqual(got, want) {
+		t.Errorf("GetMinKeys get %v, want %v", got, want)
+	}
+	this.Set("z", math.MinInt64)
+	if key, ok := this.GetMinKey(); !ok || key != "z" {
+		t.Errorf("GetMinKey get %v, want %v", key, "z")
+	}
+}
+
+func TestAllOneMinMax_Random(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	this := AllOneMinMaxConstructor[int]()
+	counts := map[int]int64{}
+	for step := 0; step < 5000; step++ {
+		key := rng.Intn(50)
+		switch rng.Intn(4) {
+		case 0:
+			this.Inc(key)
+			counts[key]++
+		case 1:
+			this.Dec(key)
+			if counts[key]--; counts[key] <= 0 {
+				delete(counts, key)
+			}
+		case 2:
+			delta := int64(rng.Intn(200) - 100)
+			this.Add(key, delta)
+			if counts[key] += delta; counts[key] <= 0 {
+				delete(counts, key)
+			}
+		default:
+			count := int64(rng.Intn(100))
+			this.Set(key, count)
+			if counts[key] = count; count <= 0 {
+				delete(counts, key)
+			}
+		}
+
+		if this.Len() != len(counts) {
+			t.Fatalf("Step %v: Len get %v, want %v", step, this.Len(), len(counts))
+		}
+		var minv, maxv int64 = math.MaxInt64, math.MinInt64
+		for key, count := range counts {
+			if got := this.Count(key); got != count {
+				t.Fatalf("Step %v: Count(%v) get %v, want %v", step, key, got, count)
+			}
+			if count < minv {
+				minv = count
+			}
+			if count > maxv {
+				maxv = count
+			}
+		}
+		if len(counts) == 0 {
+			continue
+		}
+		if key, _ := this.GetMinKey(); counts[key] != minv {
+			t.Fatalf("Step %v: GetMinKey get count %v, want %v", step, counts[key], minv)
+		}
+		if key, _ := this.GetMaxKey(); counts[key] != maxv {
+			t.Fatalf("Step %v: GetMaxKey get count %v, want %v", step, counts[key], maxv)
+		}
+	}
+}
+
+// TestAllOneMinMax_LazyIndex checks the bucket index against the bucket list, both after a
+// few recorded changes and after so many that the index has to be rebuilt.
+func TestAllOneMinMax_LazyIndex(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	this := NewAllOneMinMax[int](Options{})
+	for round := 0; round < 200; round++ {
+		for i := rng.Intn(3) + rng.Intn(2)*rng.Intn(100); i > 0; i-- {
+			if key := rng.Intn(30); rng.Intn(3) == 0 {
+				this.Dec(key)
+			} else {
+				this.Inc(key)
+			}
+		}
+		this.Set(rng.Intn(30), int64(rng.Intn(60)))
+
+		for c := int64(-1); c <= 80; c++ {
+			want := (*list.Element)(nil)
+			for ele := this.data.Front().Next(); ele != this.data.Back(); ele = ele.Next() {
+				if ele.Value.(*Node[int]).val <= c {
+					want = ele
+				}
+			}
+			if got := this.index.floor(c); got != want {
+				t.Fatalf("Round %v: floor(%v) get %v, want %v", round, c, got, want)
+			}
+		}
+	}
+}
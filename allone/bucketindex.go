@@ -0,0 +1,180 @@
+package allone
+
+import "container/list"
+
+// bucketIndex is an ordered index from count to the bucket element of AllOneMinMax, used to
+// locate a bucket far away from the current one in O(log n) expected time.
+// It is a treap whose priorities are derived from the counts, so that the shape of the
+// tree is deterministic.
+//
+// The index is maintained lazily, so that moving a key to an adjacent count stays O(1)
+// amortized: insert and erase only record the change, and floor applies the recorded
+// changes in O(log n) expected each before the lookup. Once there are more recorded changes
+// than indexed buckets, they are dropped and the next floor rebuilds the treap from the
+// bucket list in O(n) instead.
+type bucketIndex struct {
+	root *treapNode
+	// size is the number of nodes in the treap.
+	size int
+	// pending holds the changes not applied to the treap yet.
+	pending []bucketChange
+	// stale means the treap must be rebuilt from the buckets between the sentinels of data.
+	stale bool
+	data  *list.List
+	valOf func(*list.Element) int64
+}
+
+type bucketChange struct {
+	val int64
+	// ele is the created bucket, or nil if the bucket of val is removed.
+	ele *list.Element
+}
+
+type treapNode struct {
+	val  int64
+	pri  uint64
+	ele  *list.Element
+	l, r *treapNode
+}
+
+func newBucketIndex(data *list.List, valOf func(*list.Element) int64) bucketIndex {
+	return bucketIndex{data: data, valOf: valOf}
+}
+
+// splitmix64 scrambles x, see https://prng.di.unimi.it/splitmix64.c.
+func splitmix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+func newTreapNode(val int64, ele *list.Element) *treapNode {
+	return &treapNode{val: val, pri: splitmix64(uint64(val)), ele: ele}
+}
+
+// split splits t into the nodes strictly less than val and the rest.
+func split(t *treapNode, val int64) (*treapNode, *treapNode) {
+	if t == nil {
+		return nil, nil
+	}
+	if t.val < val {
+		l, r := split(t.r, val)
+		t.r = l
+		return t, r
+	}
+	l, r := split(t.l, val)
+	t.l = r
+	return l, t
+}
+
+// merge merges l and r, where all the nodes in l are less than the nodes in r.
+func merge(l, r *treapNode) *treapNode {
+	if l == nil {
+		return r
+	}
+	if r == nil {
+		return l
+	}
+	if l.pri > r.pri {
+		l.r = merge(l.r, r)
+		return l
+	}
+	r.l = merge(l, r.l)
+	return r
+}
+
+// erase removes the node of val from t, which works for any val including math.MaxInt64.
+func erase(t *treapNode, val int64) *treapNode {
+	if t == nil {
+		return nil
+	}
+	switch {
+	case val < t.val:
+		t.l = erase(t.l, val)
+	case val > t.val:
+		t.r = erase(t.r, val)
+	default:
+		return merge(t.l, t.r)
+	}
+	return t
+}
+
+// insert records that the bucket of val is created.
+func (idx *bucketIndex) insert(val int64, ele *list.Element) {
+	idx.record(bucketChange{val: val, ele: ele})
+}
+
+// erase records that the bucket of val is removed.
+func (idx *bucketIndex) erase(val int64) {
+	idx.record(bucketChange{val: val})
+}
+
+func (idx *bucketIndex) record(c bucketChange) {
+	if idx.stale {
+		return
+	}
+	if len(idx.pending) > idx.size {
+		idx.pending, idx.stale = nil, true
+		return
+	}
+	idx.pending = append(idx.pending, c)
+}
+
+// sync brings the treap up to date.
+func (idx *bucketIndex) sync() {
+	if idx.stale {
+		idx.rebuild()
+		return
+	}
+	for _, c := range idx.pending {
+		if c.ele != nil {
+			l, r := split(idx.root, c.val)
+			idx.root = merge(merge(l, newTreapNode(c.val, c.ele)), r)
+			idx.size++
+		} else {
+			idx.root = erase(idx.root, c.val)
+			idx.size--
+		}
+	}
+	idx.pending = nil
+}
+
+// rebuild builds the treap from the sorted buckets in O(n) with a stack holding the right
+// spine of the treap.
+func (idx *bucketIndex) rebuild() {
+	var spine []*treapNode
+	idx.size = 0
+	for ele := idx.data.Front().Next(); ele != idx.data.Back(); ele = ele.Next() {
+		n := newTreapNode(idx.valOf(ele), ele)
+		var last *treapNode
+		for len(spine) > 0 && spine[len(spine)-1].pri <= n.pri {
+			last, spine = spine[len(spine)-1], spine[:len(spine)-1]
+		}
+		n.l = last
+		if len(spine) > 0 {
+			spine[len(spine)-1].r = n
+		}
+		spine = append(spine, n)
+		idx.size++
+	}
+	idx.root = nil
+	if len(spine) > 0 {
+		idx.root = spine[0]
+	}
+	idx.pending, idx.stale = nil, false
+}
+
+// floor returns the bucket with the greatest count that is not greater than val, or nil.
+func (idx *bucketIndex) floor(val int64) *list.Element {
+	idx.sync()
+	var ret *list.Element
+	for t := idx.root; t != nil; {
+		if t.val <= val {
+			ret, t = t.ele, t.r
+		} else {
+			t = t.l
+		}
+	}
+	return ret
+}
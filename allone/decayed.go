@@ -0,0 +1,121 @@
+package allone
+
+import (
+	"math"
+	"time"
+)
+
+// Decayed is a counter whose counts decay exponentially over time: an event that happened
+// one half-life ago counts for 1/2, two half-lives ago for 1/4, and so on.
+//
+// Rather than rescaling all the counts as time passes, Decayed keeps for each key the score
+// log2(sum(w * 2^((t-epoch)/halfLife))) over all its events (w, t). All the decayed counts
+// share the same factor 2^(-(now-epoch)/halfLife), so the order of the scores equals the order
+// of the decayed counts, and the scores are kept in an AllOneMinMax for the max/min queries.
+// Decayed is not safe for concurrent use.
+type Decayed[K comparable] struct {
+	halfLife time.Duration
+	now      func() time.Time
+	epoch    time.Time
+	scores   map[K]float64
+	order    *AllOneMinMax[K]
+}
+
+const defaultDecayedHalfLife = time.Hour
+
+// NewDecayed returns a Decayed with the given half-life, which falls back to one hour if it
+// is not positive. A nil `now` means time.Now.
+func NewDecayed[K comparable](halfLife time.Duration, now func() time.Time) *Decayed[K] {
+	if halfLife <= 0 {
+		halfLife = defaultDecayedHalfLife
+	}
+	if now == nil {
+		now = time.Now
+	}
+	return &Decayed[K]{
+		halfLife: halfLife,
+		now:      now,
+		epoch:    now(),
+		scores:   make(map[K]float64),
+		order:    NewAllOneMinMax[K](Options{KeepNonPositive: true}),
+	}
+}
+
+// orderedBits maps a float64 to an int64 with the same order, so that the scores can be
+// stored as the counts of AllOneMinMax.
+func orderedBits(f float64) int64 {
+	b := math.Float64bits(f)
+	if b>>63 == 1 {
+		b = ^b
+	} else {
+		b |= 1 << 63
+	}
+	return int64(b ^ (1 << 63))
+}
+
+// elapsed returns the number of half-lives since the epoch.
+func (d *Decayed[K]) elapsed() float64 {
+	return float64(d.now().Sub(d.epoch)) / float64(d.halfLife)
+}
+
+// Inc records an event with weight 1 for the key.
+func (d *Decayed[K]) Inc(key K) {
+	d.Add(key, 1)
+}
+
+// Add records an event with the given weight for the key. Non-positive weights are ignored.
+func (d *Decayed[K]) Add(key K, weight float64) {
+	if weight <= 0 {
+		return
+	}
+	x := math.Log2(weight) + d.elapsed()
+	if s, ok := d.scores[key]; ok {
+		// log2(2^s + 2^x), computed without overflow.
+		hi, lo := math.Max(s, x), math.Min(s, x)
+		x = hi + math.Log1p(math.Exp2(lo-hi))/math.Ln2
+	}
+	d.scores[key] = x
+	d.order.Set(key, orderedBits(x))
+}
+
+// Count returns the decayed count of the key at now, or 0 if the key does not exist.
+func (d *Decayed[K]) Count(key K) float64 {
+	s, ok := d.scores[key]
+	if !ok {
+		return 0
+	}
+	return math.Exp2(s - d.elapsed())
+}
+
+func (d *Decayed[K]) Remove(key K) {
+	delete(d.scores, key)
+	d.order.Remove(key)
+}
+
+func (d *Decayed[K]) Len() int {
+	return len(d.scores)
+}
+
+// GetMaxKey returns the key with the greatest decayed count.
+func (d *Decayed[K]) GetMaxKey() (K, bool) {
+	return d.order.GetMaxKey()
+}
+
+// GetMinKey returns the key with the smallest decayed count.
+func (d *Decayed[K]) GetMinKey() (K, bool) {
+	return d.order.GetMinKey()
+}
+
+// Prune removes all the keys whose decayed count is less than threshold, and returns the
+// number of keys removed.
+func (d *Decayed[K]) Prune(threshold float64) int {
+	ret := 0
+	for {
+		key, ok := d.order.GetMinKey()
+		if !ok || d.Count(key) >= threshold {
+			return ret
+		}
+		d.Remove(key)
+		ret++
+	}
+}
@@ -0,0 +1,78 @@
+package allone
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestDecayed(t *testing.T) {
+	now := time.Unix(0, 0)
+	d := NewDecayed[string](time.Hour, func() time.Time { return now })
+	if _, ok := d.GetMaxKey(); ok {
+		t.Errorf("GetMaxKey on empty Decayed should fail")
+	}
+
+	// "old" gets 3 events now, "new" gets 2 events two hours later.
+	for i := 0; i < 3; i++ {
+		d.Inc("old")
+	}
+	now = now.Add(2 * time.Hour)
+	d.Inc("new")
+	d.Add("new", 1)
+	d.Add("new", -5)
+
+	if got := d.Count("old"); math.Abs(got-0.75) > 1e-9 {
+		t.Errorf("Count(old) get %v, want %v", got, 0.75)
+	}
+	if got := d.Count("new"); math.Abs(got-2) > 1e-9 {
+		t.Errorf("Count(new) get %v, want %v", got, 2)
+	}
+	if key, _ := d.GetMaxKey(); key != "new" {
+		t.Errorf("GetMaxKey get %v, want %v", key, "new")
+	}
+	if key, _ := d.GetMinKey(); key != "old" {
+		t.Errorf("GetMinKey get %v, want %v", key, "old")
+	}
+
+	// A burst on "old" brings it back to the top.
+	for i := 0; i < 2; i++ {
+		d.Inc("old")
+	}
+	if key, _ := d.GetMaxKey(); key != "old" {
+		t.Errorf("GetMaxKey get %v, want %v", key, "old")
+	}
+
+	now = now.Add(10 * time.Hour)
+	if n := d.Prune(0.0025); n != 1 || d.Len() != 1 {
+		t.Errorf("Prune get %v, Len get %v, want %v, %v", n, d.Len(), 1, 1)
+	}
+	if key, _ := d.GetMaxKey(); key != "old" {
+		t.Errorf("GetMaxKey get %v, want %v", key, "old")
+	}
+}
+
+func TestOrderedBits(t *testing.T) {
+	values := []float64{math.Inf(-1), -1e300, -2.5, -1, -1e-300, 0, 1e-300, 1, 2.5, 1e300, math.Inf(1)}
+	for i := 1; i < len(values); i++ {
+		if orderedBits(values[i-1]) >= orderedBits(values[i]) {
+			t.Errorf("orderedBits(%v) >= orderedBits(%v)", values[i-1], values[i])
+		}
+	}
+}
+
+func TestDecayed_NonPositiveHalfLife(t *testing.T) {
+	now := time.Unix(0, 0)
+	for _, halfLife := range []time.Duration{0, -time.Minute} {
+		d := NewDecayed[string](halfLife, func() time.Time { return now })
+		d.Inc("a")
+		now = now.Add(time.Hour)
+		d.Inc("b")
+		if got := d.Count("a"); math.Abs(got-0.5) > 1e-9 {
+			t.Errorf("Count(a) get %v, want %v", got, 0.5)
+		}
+		if key, _ := d.GetMaxKey(); key != "b" {
+			t.Errorf("GetMaxKey get %v, want %v", key, "b")
+		}
+	}
+}
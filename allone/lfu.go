@@ -0,0 +1,97 @@
+package allone
+
+// LFUStats records the counters of LFUCache.
+type LFUStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+}
+
+// LFUCache is a least-frequently-used cache built on the frequency buckets of AllOneMinMax.
+// Among the keys with the same frequency, the least recently used one will be evicted first,
+// since the keys in a bucket are kept in arrival order.
+// LFUCache is not safe for concurrent use.
+type LFUCache[K comparable, V any] struct {
+	capacity int
+	freq     *AllOneMinMax[K]
+	values   map[K]V
+	onEvict  func(K, V)
+	stats    LFUStats
+}
+
+// NewLFUCache returns a LFUCache holding at most `capacity` entries. `onEvict` will be called
+// with every entry evicted due to the capacity, and can be nil.
+func NewLFUCache[K comparable, V any](capacity int, onEvict func(K, V)) *LFUCache[K, V] {
+	return &LFUCache[K, V]{
+		capacity: capacity,
+		freq:     NewAllOneMinMax[K](Options{TieBreak: TieBreakEarliest}),
+		values:   make(map[K]V, capacity),
+		onEvict:  onEvict,
+	}
+}
+
+// Get returns the value of the key and increases its frequency.
+func (c *LFUCache[K, V]) Get(key K) (V, bool) {
+	v, ok := c.values[key]
+	if !ok {
+		c.stats.Misses++
+		return v, false
+	}
+	c.stats.Hits++
+	c.freq.Inc(key)
+	return v, true
+}
+
+// Put sets the value of the key and increases its frequency. If the key is new and the cache
+// is full, the least frequently used entry will be evicted first.
+func (c *LFUCache[K, V]) Put(key K, value V) {
+	if c.capacity <= 0 {
+		return
+	}
+	if _, ok := c.values[key]; !ok && len(c.values) >= c.capacity {
+		c.evict()
+	}
+	c.values[key] = value
+	c.freq.Inc(key)
+}
+
+// Delete removes the key without calling the eviction callback.
+func (c *LFUCache[K, V]) Delete(key K) bool {
+	if _, ok := c.values[key]; !ok {
+		return false
+	}
+	delete(c.values, key)
+	c.freq.Remove(key)
+	return true
+}
+
+// Frequency returns the number of accesses of the key since it was put into the cache.
+func (c *LFUCache[K, V]) Frequency(key K) int64 {
+	return c.freq.Count(key)
+}
+
+func (c *LFUCache[K, V]) Len() int {
+	return len(c.values)
+}
+
+func (c *LFUCache[K, V]) Capacity() int {
+	return c.capacity
+}
+
+func (c *LFUCache[K, V]) Stats() LFUStats {
+	return c.stats
+}
+
+func (c *LFUCache[K, V]) evict() {
+	key, ok := c.freq.GetMinKey()
+	if !ok {
+		return
+	}
+	value := c.values[key]
+	delete(c.values, key)
+	c.freq.Remove(key)
+	c.stats.Evictions++
+	if c.onEvict != nil {
+		c.onEvict(key, value)
+	}
+}
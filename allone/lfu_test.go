@@ -0,0 +1,69 @@
+package allone
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLFUCache(t *testing.T) {
+	var evicted []int
+	c := NewLFUCache[int, string](2, func(k int, _ string) {
+		evicted = append(evicted, k)
+	})
+
+	c.Put(1, "one")
+	c.Put(2, "two")
+	if v, ok := c.Get(1); !ok || v != "one" {
+		t.Errorf("Get(1) get %v, %v, want %v, %v", v, ok, "one", true)
+	}
+	// 2 has the lowest frequency.
+	c.Put(3, "three")
+	if _, ok := c.Get(2); ok {
+		t.Errorf("Get(2) should miss")
+	}
+	if v, ok := c.Get(3); !ok || v != "three" {
+		t.Errorf("Get(3) get %v, %v, want %v, %v", v, ok, "three", true)
+	}
+	// 1 and 3 are tied, and 1 is the least recently used one.
+	c.Put(4, "four")
+	if _, ok := c.Get(1); ok {
+		t.Errorf("Get(1) should miss")
+	}
+	if v, ok := c.Get(3); !ok || v != "three" {
+		t.Errorf("Get(3) get %v, %v, want %v, %v", v, ok, "three", true)
+	}
+	if v, ok := c.Get(4); !ok || v != "four" {
+		t.Errorf("Get(4) get %v, %v, want %v, %v", v, ok, "four", true)
+	}
+
+	// Updating an existing key never evicts.
+	c.Put(4, "FOUR")
+	if v, _ := c.Get(4); v != "FOUR" {
+		t.Errorf("Get(4) get %v, want %v", v, "FOUR")
+	}
+	if got := c.Frequency(4); got != 4 {
+		t.Errorf("Frequency(4) get %v, want %v", got, 4)
+	}
+
+	if !c.Delete(3) || c.Delete(3) {
+		t.Errorf("Delete(3) should succeed only once")
+	}
+	if c.Len() != 1 {
+		t.Errorf("Len get %v, want %v", c.Len(), 1)
+	}
+
+	if want := []int{2, 1}; !reflect.DeepEqual(evicted, want) {
+		t.Errorf("Evicted %v, want %v", evicted, want)
+	}
+	if got, want := c.Stats(), (LFUStats{Hits: 5, Misses: 2, Evictions: 2}); got != want {
+		t.Errorf("Stats get %+v, want %+v", got, want)
+	}
+}
+
+func TestLFUCache_ZeroCapacity(t *testing.T) {
+	c := NewLFUCache[int, int](0, nil)
+	c.Put(1, 1)
+	if _, ok := c.Get(1); ok || c.Len() != 0 {
+		t.Errorf("Zero capacity cache should hold nothing")
+	}
+}
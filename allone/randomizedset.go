@@ -0,0 +1,116 @@
+package allone
+
+import "math/rand"
+
+// RandomizedSet is the generic version of AllOneAddDel. It keeps the items in a dense slice
+// and their positions in a map, so that Add, Del, Exist and GetRandom all cost O(1).
+// Methods with randomness take a *rand.Rand for deterministic tests, and a nil one means the
+// default source of math/rand.
+type RandomizedSet[T comparable] struct {
+	hash  map[T]int
+	items []T
+}
+
+func NewRandomizedSet[T comparable]() *RandomizedSet[T] {
+	return &RandomizedSet[T]{
+		hash:  make(map[T]int),
+		items: make([]T, 0),
+	}
+}
+
+func (s *RandomizedSet[T]) Add(x T) bool {
+	if _, ok := s.hash[x]; ok {
+		return false
+	}
+	s.hash[x] = len(s.items)
+	s.items = append(s.items, x)
+	return true
+}
+
+// Del swaps the item with the last one and truncates the slice.
+func (s *RandomizedSet[T]) Del(x T) bool {
+	px, ok := s.hash[x]
+	if !ok {
+		return false
+	}
+	last := len(s.items) - 1
+	y := s.items[last]
+	s.items[px], s.hash[y] = y, px
+	delete(s.hash, x)
+	var zero T
+	s.items[last] = zero
+	s.items = s.items[:last]
+	return true
+}
+
+func (s *RandomizedSet[T]) Exist(x T) bool {
+	_, ok := s.hash[x]
+	return ok
+}
+
+func (s *RandomizedSet[T]) Len() int {
+	return len(s.items)
+}
+
+// GetRandom returns an item chosen uniformly at random.
+func (s *RandomizedSet[T]) GetRandom(rng *rand.Rand) (T, bool) {
+	if len(s.items) == 0 {
+		var zero T
+		return zero, false
+	}
+	return s.items[intn(rng, len(s.items))], true
+}
+
+// PopRandom removes and returns an item chosen uniformly at random.
+func (s *RandomizedSet[T]) PopRandom(rng *rand.Rand) (T, bool) {
+	x, ok := s.GetRandom(rng)
+	if ok {
+		s.Del(x)
+	}
+	return x, ok
+}
+
+// SampleK returns min(k, Len()) distinct items chosen uniformly at random in O(k). The items
+// stay in the set, though their internal order is shuffled.
+func (s *RandomizedSet[T]) SampleK(rng *rand.Rand, k int) []T {
+	n := len(s.items)
+	if k > n {
+		k = n
+	}
+	if k <= 0 {
+		return nil
+	}
+	// Partial Fisher-Yates shuffle on the tail of the slice.
+	for i := n - 1; i >= n-k; i-- {
+		j := intn(rng, i+1)
+		x, y := s.items[i], s.items[j]
+		s.items[i], s.items[j] = y, x
+		s.hash[x], s.hash[y] = j, i
+	}
+	ret := make([]T, k)
+	copy(ret, s.items[n-k:])
+	return ret
+}
+
+// Range traverses all the items and stops if f returns false.
+func (s *RandomizedSet[T]) Range(f func(T) bool) {
+	for _, x := range s.items {
+		if !f(x) {
+			return
+		}
+	}
+}
+
+// Slice returns a copy of all the items.
+func (s *RandomizedSet[T]) Slice() []T {
+	ret := make([]T, len(s.items))
+	copy(ret, s.items)
+	return ret
+}
+
+func intn(rng *rand.Rand, n int) int {
+	if rng == nil {
+		return rand.Intn(n)
+	}
+	return rng.Intn(n)
+}
@@ -0,0 +1,93 @@
+package allone
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestRandomizedSet(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	s := NewRandomizedSet[int]()
+	if _, ok := s.GetRandom(rng); ok {
+		t.Errorf("GetRandom on empty set should fail")
+	}
+	for i := 0; i < 10; i++ {
+		s.Add(i)
+	}
+	if s.Add(0) || !s.Del(3) || s.Del(3) {
+		t.Errorf("Add or Del get unexpected result")
+	}
+	if s.Len() != 9 || s.Exist(3) || !s.Exist(9) {
+		t.Errorf("Len get %v, want %v", s.Len(), 9)
+	}
+
+	seen := map[int]int{}
+	for i := 0; i < 9000; i++ {
+		x, _ := s.GetRandom(rng)
+		seen[x]++
+	}
+	for x, n := range seen {
+		if x == 3 || n < 800 || n > 1200 {
+			t.Errorf("GetRandom get %v for %v times", x, n)
+		}
+	}
+
+	sample := s.SampleK(rng, 4)
+	if len(sample) != 4 {
+		t.Errorf("SampleK get %v items, want %v", len(sample), 4)
+	}
+	distinct := map[int]bool{}
+	for _, x := range sample {
+		if !s.Exist(x) || distinct[x] {
+			t.Errorf("SampleK get invalid item %v in %v", x, sample)
+		}
+		distinct[x] = true
+	}
+	if got := s.SampleK(rng, 100); len(got) != 9 {
+		t.Errorf("SampleK get %v items, want %v", len(got), 9)
+	}
+
+	// The set must stay consistent after shuffling.
+	count := 0
+	s.Range(func(int) bool {
+		count++
+		return true
+	})
+	if count != 9 {
+		t.Errorf("Range visit %v items, want %v", count, 9)
+	}
+	for _, x := range s.Slice() {
+		if !s.Del(x) {
+			t.Errorf("Del(%v) failed", x)
+		}
+	}
+
+	s.Add(1)
+	s.Add(2)
+	popped := map[int]bool{}
+	for i := 0; i < 2; i++ {
+		x, ok := s.PopRandom(rng)
+		if !ok || popped[x] {
+			t.Errorf("PopRandom get %v, %v", x, ok)
+		}
+		popped[x] = true
+	}
+	if _, ok := s.PopRandom(nil); ok || s.Len() != 0 {
+		t.Errorf("PopRandom on empty set should fail")
+	}
+}
+
+func TestRandomizedSet_Deterministic(t *testing.T) {
+	sample := func() []int {
+		rng := rand.New(rand.NewSource(42))
+		s := NewRandomizedSet[int]()
+		for i := 0; i < 100; i++ {
+			s.Add(i)
+		}
+		return s.SampleK(rng, 5)
+	}
+	if a, b := sample(), sample(); !reflect.DeepEqual(a, b) {
+		t.Errorf("SampleK with the same seed get %v and %v", a, b)
+	}
+}
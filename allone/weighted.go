@@ -0,0 +1,139 @@
+package allone
+
+import "math/rand"
+
+// Weighted keeps the items in a dense slice like AllOneAddDel, together with a Fenwick tree
+// over their weights, so that an item can be picked with probability proportional to its
+// weight. Add, Update, Del and Pick all cost O(log n).
+// Weighted is not safe for concurrent use.
+type Weighted[T comparable] struct {
+	hash    map[T]int
+	items   []T
+	weights []int64
+	// tree is the 1-indexed Fenwick tree over weights.
+	tree  []int64
+	total int64
+}
+
+func NewWeighted[T comparable]() *Weighted[T] {
+	return &Weighted[T]{
+		hash: make(map[T]int),
+		tree: make([]int64, 1),
+	}
+}
+
+func lowbit(x int) int {
+	return x & -x
+}
+
+func (w *Weighted[T]) fenwickAdd(i int, v int64) {
+	for i++; i < len(w.tree); i += lowbit(i) {
+		w.tree[i] += v
+	}
+}
+
+// fenwickSum returns the sum of the first i weights.
+func (w *Weighted[T]) fenwickSum(i int) int64 {
+	ret := int64(0)
+	for ; i > 0; i -= lowbit(i) {
+		ret += w.tree[i]
+	}
+	return ret
+}
+
+// Add inserts the item with the given weight. Returns false if the item exists or the weight
+// is negative.
+func (w *Weighted[T]) Add(x T, weight int64) bool {
+	if _, ok := w.hash[x]; ok || weight < 0 {
+		return false
+	}
+	n := len(w.items) + 1
+	w.hash[x] = len(w.items)
+	w.items = append(w.items, x)
+	w.weights = append(w.weights, weight)
+	// tree[n] covers the weights in (n-lowbit(n), n].
+	w.tree = append(w.tree, weight+w.fenwickSum(n-1)-w.fenwickSum(n-lowbit(n)))
+	w.total += weight
+	return true
+}
+
+// Update changes the weight of the item. Returns false if the item does not exist or the
+// weight is negative.
+func (w *Weighted[T]) Update(x T, weight int64) bool {
+	i, ok := w.hash[x]
+	if !ok || weight < 0 {
+		return false
+	}
+	delta := weight - w.weights[i]
+	w.weights[i] = weight
+	w.fenwickAdd(i, delta)
+	w.total += delta
+	return true
+}
+
+// Del swaps the item with the last one and truncates the slice.
+func (w *Weighted[T]) Del(x T) bool {
+	px, ok := w.hash[x]
+	if !ok {
+		return false
+	}
+	last := len(w.items) - 1
+	y := w.items[last]
+	w.Update(x, w.weights[last])
+	w.items[px], w.hash[y] = y, px
+	delete(w.hash, x)
+
+	w.total -= w.weights[last]
+	var zero T
+	w.items[last] = zero
+	w.items, w.weights, w.tree = w.items[:last], w.weights[:last], w.tree[:last+1]
+	return true
+}
+
+func (w *Weighted[T]) Exist(x T) bool {
+	_, ok := w.hash[x]
+	return ok
+}
+
+// Weight returns the weight of the item, or 0 if the item does not exist.
+func (w *Weighted[T]) Weight(x T) int64 {
+	if i, ok := w.hash[x]; ok {
+		return w.weights[i]
+	}
+	return 0
+}
+
+func (w *Weighted[T]) Len() int {
+	return len(w.items)
+}
+
+// Total returns the sum of all the weights.
+func (w *Weighted[T]) Total() int64 {
+	return w.total
+}
+
+// Pick returns an item with probability proportional to its weight. Items with weight 0 are
+// never picked. A nil rng means the default source of math/rand.
+func (w *Weighted[T]) Pick(rng *rand.Rand) (T, bool) {
+	if w.total <= 0 {
+		var zero T
+		return zero, false
+	}
+	var r int64
+	if rng == nil {
+		r = rand.Int63n(w.total)
+	} else {
+		r = rng.Int63n(w.total)
+	}
+	// Find the first index whose prefix sum is greater than r by binary lifting.
+	pos, step := 0, 1
+	for step*2 < len(w.tree) {
+		step *= 2
+	}
+	for ; step > 0; step >>= 1 {
+		if next := pos + step; next < len(w.tree) && w.tree[next] <= r {
+			pos, r = next, r-w.tree[next]
+		}
+	}
+	return w.items[pos], true
+}
@@ -0,0 +1,115 @@
+package allone
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestWeighted(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	w := NewWeighted[string]()
+	if _, ok := w.Pick(rng); ok {
+		t.Errorf("Pick on empty Weighted should fail")
+	}
+
+	weights := map[string]int64{"a": 1, "b": 2, "c": 0, "d": 3, "e": 4}
+	for _, x := range []string{"a", "b", "c", "d", "e"} {
+		if !w.Add(x, weights[x]) {
+			t.Errorf("Add(%v) failed", x)
+		}
+	}
+	if w.Add("a", 1) || w.Add("f", -1) {
+		t.Errorf("Add should fail on existing item or negative weight")
+	}
+	if w.Total() != 10 || w.Len() != 5 {
+		t.Errorf("Total get %v, Len get %v, want %v, %v", w.Total(), w.Len(), 10, 5)
+	}
+
+	check := func(weights map[string]int64) {
+		t.Helper()
+		total := int64(0)
+		for _, weight := range weights {
+			total += weight
+		}
+		const n = 20000
+		seen := map[string]int{}
+		for i := 0; i < n; i++ {
+			x, ok := w.Pick(rng)
+			if !ok {
+				t.Fatalf("Pick failed")
+			}
+			seen[x]++
+		}
+		for x, weight := range weights {
+			want := float64(n) * float64(weight) / float64(total)
+			if got := float64(seen[x]); got < want*0.9 || got > want*1.1 {
+				t.Errorf("Pick get %v for %v times, want about %v", x, got, want)
+			}
+		}
+	}
+	check(weights)
+
+	w.Update("c", 6)
+	w.Update("e", 0)
+	if w.Update("x", 1) {
+		t.Errorf("Update should fail on missing item")
+	}
+	// "a" is swapped with the last item "e".
+	w.Del("a")
+	if w.Del("a") || w.Exist("a") {
+		t.Errorf("Del(a) should succeed only once")
+	}
+	weights = map[string]int64{"b": 2, "c": 6, "d": 3, "e": 0}
+	if w.Total() != 11 || w.Weight("c") != 6 || w.Weight("a") != 0 {
+		t.Errorf("Total get %v, want %v", w.Total(), 11)
+	}
+	check(weights)
+
+	for _, x := range []string{"b", "c", "d", "e"} {
+		w.Del(x)
+	}
+	if w.Len() != 0 || w.Total() != 0 {
+		t.Errorf("Len get %v, Total get %v, want empty", w.Len(), w.Total())
+	}
+	w.Add("z", 5)
+	if x, ok := w.Pick(nil); !ok || x != "z" {
+		t.Errorf("Pick get %v, %v, want %v, %v", x, ok, "z", true)
+	}
+}
+
+func TestWeighted_Random(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	w := NewWeighted[int]()
+	weights := map[int]int64{}
+	for step := 0; step < 3000; step++ {
+		x := rng.Intn(100)
+		switch rng.Intn(3) {
+		case 0:
+			if w.Add(x, int64(rng.Intn(10))) {
+				weights[x] = w.Weight(x)
+			}
+		case 1:
+			weight := int64(rng.Intn(10))
+			if w.Update(x, weight) {
+				weights[x] = weight
+			}
+		default:
+			w.Del(x)
+			delete(weights, x)
+		}
+	}
+	// Every prefix sum of the Fenwick tree must match the dense slice.
+	sum := int64(0)
+	for i, weight := range w.weights {
+		if weights[w.items[i]] != weight {
+			t.Fatalf("Weight of %v get %v, want %v", w.items[i], weight, weights[w.items[i]])
+		}
+		sum += weight
+		if got := w.fenwickSum(i + 1); got != sum {
+			t.Fatalf("Prefix sum %v get %v, want %v", i+1, got, sum)
+		}
+	}
+	if w.Total() != sum || w.Len() != len(weights) {
+		t.Errorf("Total get %v, want %v", w.Total(), sum)
+	}
+}
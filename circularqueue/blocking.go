@@ -0,0 +1,176 @@
+package circularqueue
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrClosed is returned when putting into a closed Blocking, or taking from a closed and
+// drained Blocking.
+var ErrClosed = errors.New("circularqueue: queue closed")
+
+// Blocking is a bounded queue that is safe for concurrent use. Put blocks while the queue is
+// full and Take blocks while it is empty.
+// Close behaves like closing a channel: further Puts fail, while Takes keep draining the
+// remaining items before failing with ErrClosed.
+type Blocking[T any] struct {
+	mu     sync.Mutex
+	q      *CircularQueue[T]
+	closed bool
+	// changed is closed and replaced whenever the queue changes, to wake up all the waiters.
+	changed chan struct{}
+}
+
+func NewBlocking[T any](size int) *Blocking[T] {
+	return &Blocking[T]{
+		q:       NewCircularQueue[T](size),
+		changed: make(chan struct{}),
+	}
+}
+
+// Put appends the item to the tail, blocking while the queue is full.
+func (b *Blocking[T]) Put(ctx context.Context, item T) error {
+	for {
+		b.mu.Lock()
+		if b.closed {
+			b.mu.Unlock()
+			return ErrClosed
+		}
+		if b.q.EnQueue(item) {
+			b.notify()
+			b.mu.Unlock()
+			return nil
+		}
+		changed := b.changed
+		b.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// Take removes and returns the head, blocking while the queue is empty.
+func (b *Blocking[T]) Take(ctx context.Context) (T, error) {
+	for {
+		b.mu.Lock()
+		if item, ok := b.q.DeQueue(); ok {
+			b.notify()
+			b.mu.Unlock()
+			return item, nil
+		}
+		if b.closed {
+			b.mu.Unlock()
+			var zero T
+			return zero, ErrClosed
+		}
+		changed := b.changed
+		b.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// TryPut appends the item without blocking. Returns false if the queue is full or closed.
+func (b *Blocking[T]) TryPut(item T) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed || !b.q.EnQueue(item) {
+		return false
+	}
+	b.notify()
+	return true
+}
+
+// TryTake removes and returns the head without blocking.
+func (b *Blocking[T]) TryTake() (T, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	item, ok := b.q.DeQueue()
+	if ok {
+		b.notify()
+	}
+	return item, ok
+}
+
+// DrainTo moves up to len(buf) items into buf without blocking and returns the number of
+// items moved.
+func (b *Blocking[T]) DrainTo(buf []T) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	n := 0
+	for ; n < len(buf); n++ {
+		item, ok := b.q.DeQueue()
+		if !ok {
+			break
+		}
+		buf[n] = item
+	}
+	if n > 0 {
+		b.notify()
+	}
+	return n
+}
+
+// Close closes the queue and wakes up all the blocked callers. Closing a closed queue is a no-op.
+func (b *Blocking[T]) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	b.closed = true
+	b.notify()
+}
+
+func (b *Blocking[T]) IsClosed() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.closed
+}
+
+// Front returns the head without removing it.
+func (b *Blocking[T]) Front() (T, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.q.At(0)
+}
+
+// Rear returns the tail without removing it.
+func (b *Blocking[T]) Rear() (T, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.q.At(b.q.CountUsed() - 1)
+}
+
+func (b *Blocking[T]) Size() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.q.Size()
+}
+
+func (b *Blocking[T]) CountUsed() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.q.CountUsed()
+}
+
+func (b *Blocking[T]) CountUnused() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.q.CountUnused()
+}
+
+// notify wakes up all the waiters. It must be called with b.mu held.
+func (b *Blocking[T]) notify() {
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
@@ -0,0 +1,108 @@
+package circularqueue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBlocking_PutTake(t *testing.T) {
+	b := NewBlocking[int](2)
+	ctx := context.Background()
+
+	const n = 100
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < n; i++ {
+			if err := b.Put(ctx, i); err != nil {
+				t.Errorf("Put() = %v, want nil", err)
+			}
+		}
+		b.Close()
+	}()
+
+	for i := 0; ; i++ {
+		item, err := b.Take(ctx)
+		if errors.Is(err, ErrClosed) {
+			if i != n {
+				t.Errorf("Take() got %v items, want %v", i, n)
+			}
+			break
+		}
+		if err != nil || item != i {
+			t.Fatalf("Take() = %v, %v, want %v, nil", item, err, i)
+		}
+	}
+	wg.Wait()
+
+	if err := b.Put(ctx, 0); !errors.Is(err, ErrClosed) {
+		t.Errorf("Put() after Close() = %v, want %v", err, ErrClosed)
+	}
+}
+
+func TestBlocking_Context(t *testing.T) {
+	b := NewBlocking[int](1)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := b.Take(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Take() = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if !b.TryPut(1) {
+		t.Errorf("TryPut() = %v, want %v", false, true)
+	}
+	if err := b.Put(ctx, 2); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Put() = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestBlocking_CloseWakesUp(t *testing.T) {
+	b := NewBlocking[int](1)
+	done := make(chan error)
+	go func() {
+		_, err := b.Take(context.Background())
+		done <- err
+	}()
+	time.Sleep(5 * time.Millisecond)
+	b.Close()
+	if err := <-done; !errors.Is(err, ErrClosed) {
+		t.Errorf("Take() = %v, want %v", err, ErrClosed)
+	}
+}
+
+func TestBlocking_NonBlocking(t *testing.T) {
+	b := NewBlocking[int](3)
+	if _, ok := b.TryTake(); ok {
+		t.Errorf("TryTake() on empty queue should fail")
+	}
+	for i := 0; i < 3; i++ {
+		b.TryPut(i)
+	}
+	if b.TryPut(3) {
+		t.Errorf("TryPut() on full queue should fail")
+	}
+	if item, ok := b.Front(); !ok || item != 0 {
+		t.Errorf("Front() = %v, %v, want %v, %v", item, ok, 0, true)
+	}
+	if item, ok := b.Rear(); !ok || item != 2 {
+		t.Errorf("Rear() = %v, %v, want %v, %v", item, ok, 2, true)
+	}
+	if b.CountUsed() != 3 || b.CountUnused() != 0 || b.Size() != 3 {
+		t.Errorf("Unexpected counters: used=%v unused=%v size=%v", b.CountUsed(), b.CountUnused(), b.Size())
+	}
+
+	buf := make([]int, 2)
+	if n := b.DrainTo(buf); n != 2 || buf[0] != 0 || buf[1] != 1 {
+		t.Errorf("DrainTo() = %v, %v, want %v, %v", n, buf, 2, []int{0, 1})
+	}
+	if item, ok := b.TryTake(); !ok || item != 2 {
+		t.Errorf("TryTake() = %v, %v, want %v, %v", item, ok, 2, true)
+	}
+	if _, ok := b.Rear(); ok {
+		t.Errorf("Rear() on empty queue should fail")
+	}
+}
@@ -2,77 +2,132 @@ package circularqueue
 
 const defaultCircularQueueSize = 10
 
-type CircularQueue struct {
-	head int
-	tail int
-	size int
-	data []any
+// FullPolicy defines the behavior of EnQueue when the queue is full.
+type FullPolicy int
+
+const (
+	// PolicyReject makes EnQueue return false when the queue is full.
+	PolicyReject FullPolicy = iota
+	// PolicyGrow doubles the size of the queue when it is full.
+	PolicyGrow
+	// PolicyOverwrite drops the oldest item to make room for the new one, so the queue
+	// always keeps the last Size() items.
+	PolicyOverwrite
+)
+
+type CircularQueue[T any] struct {
+	head   int
+	tail   int
+	size   int
+	data   []T
+	policy FullPolicy
 }
 
-func NewCircularQueue(size int) *CircularQueue {
+func NewCircularQueue[T any](size int) *CircularQueue[T] {
+	return NewCircularQueueWithPolicy[T](size, PolicyReject)
+}
+
+func NewCircularQueueWithPolicy[T any](size int, policy FullPolicy) *CircularQueue[T] {
 	if size <= 0 {
 		size = defaultCircularQueueSize
 	}
-	return &CircularQueue{
-		head: 0,
-		tail: 0,
-		size: size,
-		data: make([]any, size+1),
+	return &CircularQueue[T]{
+		head:   0,
+		tail:   0,
+		size:   size,
+		data:   make([]T, size+1),
+		policy: policy,
 	}
 }
 
-func (q *CircularQueue) Size() int {
+func (q *CircularQueue[T]) Size() int {
 	return q.size
 }
 
-func (q *CircularQueue) CountUsed() int {
+func (q *CircularQueue[T]) CountUsed() int {
 	if q.tail >= q.head {
 		return q.tail - q.head
 	}
 	return q.tail + q.size + 1 - q.head
 }
 
-func (q *CircularQueue) CountUnused() int {
+func (q *CircularQueue[T]) CountUnused() int {
 	return q.Size() - q.CountUsed()
 }
 
-func (q *CircularQueue) IsEmpty() bool {
+func (q *CircularQueue[T]) IsEmpty() bool {
 	return q.tail == q.head
 }
 
-func (q *CircularQueue) IsFull() bool {
+func (q *CircularQueue[T]) IsFull() bool {
 	return (q.tail+1)%(q.size+1) == q.head
 }
 
-func (q *CircularQueue) EnQueue(item any) bool {
+// EnQueue appends the item to the tail of the queue. When the queue is full, the result
+// depends on the FullPolicy of the queue.
+func (q *CircularQueue[T]) EnQueue(item T) bool {
 	if q.IsFull() {
-		return false
+		switch q.policy {
+		case PolicyGrow:
+			q.grow()
+		case PolicyOverwrite:
+			q.DeQueue()
+		default:
+			return false
+		}
 	}
 	q.data[q.tail] = item
 	q.tail = (q.tail + 1) % (q.size + 1)
 	return true
 }
 
-func (q *CircularQueue) DeQueue() (any, bool) {
+func (q *CircularQueue[T]) DeQueue() (T, bool) {
+	var zero T
 	if q.IsEmpty() {
-		return nil, false
+		return zero, false
 	}
 	item := q.data[q.head]
+	// Release the reference so that the item can be reclaimed.
+	q.data[q.head] = zero
 	q.head = (q.head + 1) % (q.size + 1)
 	return item, true
 }
 
-func (q *CircularQueue) Front() any {
+// Front returns the oldest item, or the zero value if the queue is empty.
+func (q *CircularQueue[T]) Front() T {
 	if q.IsEmpty() {
-		return nil
+		var zero T
+		return zero
 	}
 	return q.data[q.head]
 }
 
-func (q *CircularQueue) Rear() any {
+// Rear returns the newest item, or the zero value if the queue is empty.
+func (q *CircularQueue[T]) Rear() T {
 	if q.IsEmpty() {
-		return nil
+		var zero T
+		return zero
 	}
 	idx := (q.tail - 1 + q.size + 1) % (q.size + 1)
 	return q.data[idx]
 }
+
+// Clear removes all items and zeroes out every slot.
+func (q *CircularQueue[T]) Clear() {
+	var zero T
+	for i := range q.data {
+		q.data[i] = zero
+	}
+	q.head, q.tail = 0, 0
+}
+
+// grow doubles the size of the queue and moves all the items to the beginning of the new slots.
+func (q *CircularQueue[T]) grow() {
+	size := q.size * 2
+	data := make([]T, size+1)
+	n := q.CountUsed()
+	for i := 0; i < n; i++ {
+		data[i] = q.data[(q.head+i)%(q.size+1)]
+	}
+	q.head, q.tail, q.size, q.data = 0, n, size, data
+}
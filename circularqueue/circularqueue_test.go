@@ -30,7 +30,7 @@ func TestCircularQueue_Size(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			q := NewCircularQueue(tt.size)
+			q := NewCircularQueue[any](tt.size)
 			if got := q.Size(); got != tt.want {
 				t.Errorf("CircularQueue.Size() = %v, want %v", got, tt.want)
 			}
@@ -87,7 +87,7 @@ func TestCircularQueue_CountUsed(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			q := &CircularQueue{
+			q := &CircularQueue[any]{
 				head: tt.fields.head,
 				tail: tt.fields.tail,
 				size: tt.fields.size,
@@ -149,7 +149,7 @@ func TestCircularQueue_CountUnused(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			q := &CircularQueue{
+			q := &CircularQueue[any]{
 				head: tt.fields.head,
 				tail: tt.fields.tail,
 				size: tt.fields.size,
@@ -163,22 +163,99 @@ func TestCircularQueue_CountUnused(t *testing.T) {
 }
 
 func TestCircularQueue_All(t *testing.T) {
-	q := NewCircularQueue(10)
+	q := NewCircularQueue[int](10)
 	for i := 0; i < 10; i++ {
 		if ok := q.EnQueue(i); !ok {
 			t.Errorf("Step 1: EnQueue() = %v, want %v", ok, true)
 		}
 	}
 
-	if item := q.Front(); item.(int) != 0 {
-		t.Errorf("Step 2: Front() = %v, want %v", item.(int), 0)
+	if item := q.Front(); item != 0 {
+		t.Errorf("Step 2: Front() = %v, want %v", item, 0)
 	}
 
-	if item, ok := q.DeQueue(); !ok || item.(int) != 0 {
-		t.Errorf("Step 3: DeQueue() = %v, %v, want %v, %v", item.(int), ok, 0, true)
+	if item, ok := q.DeQueue(); !ok || item != 0 {
+		t.Errorf("Step 3: DeQueue() = %v, %v, want %v, %v", item, ok, 0, true)
 	}
 
-	if item := q.Rear(); item.(int) != 9 {
-		t.Errorf("Step 4: Rear() = %v, want %v", item.(int), 9)
+	if item := q.Rear(); item != 9 {
+		t.Errorf("Step 4: Rear() = %v, want %v", item, 9)
+	}
+}
+
+func TestCircularQueue_Policy(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   FullPolicy
+		wantOk   []bool
+		wantSize int
+		want     []int
+	}{
+		{
+			name:     "reject",
+			policy:   PolicyReject,
+			wantOk:   []bool{true, true, true, false, false},
+			wantSize: 3,
+			want:     []int{0, 1, 2},
+		},
+		{
+			name:     "grow",
+			policy:   PolicyGrow,
+			wantOk:   []bool{true, true, true, true, true},
+			wantSize: 6,
+			want:     []int{0, 1, 2, 3, 4},
+		},
+		{
+			name:     "overwrite",
+			policy:   PolicyOverwrite,
+			wantOk:   []bool{true, true, true, true, true},
+			wantSize: 3,
+			want:     []int{2, 3, 4},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewCircularQueueWithPolicy[int](3, tt.policy)
+			// Move head away from the first slot so that wrapping is exercised.
+			q.EnQueue(-1)
+			q.DeQueue()
+			for i, want := range tt.wantOk {
+				if ok := q.EnQueue(i); ok != want {
+					t.Errorf("EnQueue(%v) = %v, want %v", i, ok, want)
+				}
+			}
+			if got := q.Size(); got != tt.wantSize {
+				t.Errorf("Size() = %v, want %v", got, tt.wantSize)
+			}
+			if got := q.CountUsed(); got != len(tt.want) {
+				t.Errorf("CountUsed() = %v, want %v", got, len(tt.want))
+			}
+			for _, want := range tt.want {
+				if got, ok := q.DeQueue(); !ok || got != want {
+					t.Errorf("DeQueue() = %v, %v, want %v, %v", got, ok, want, true)
+				}
+			}
+		})
+	}
+}
+
+func TestCircularQueue_Clear(t *testing.T) {
+	q := NewCircularQueue[*int](3)
+	for i := 0; i < 3; i++ {
+		v := i
+		q.EnQueue(&v)
+	}
+	q.DeQueue()
+	if q.data[0] != nil {
+		t.Errorf("DeQueue() should zero out the slot, got %v", q.data[0])
+	}
+	q.Clear()
+	if !q.IsEmpty() {
+		t.Errorf("IsEmpty() = %v, want %v", false, true)
+	}
+	for i, item := range q.data {
+		if item != nil {
+			t.Errorf("Clear() should zero out slot %v, got %v", i, item)
+		}
 	}
 }
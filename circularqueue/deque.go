@@ -0,0 +1,124 @@
+package circularqueue
+
+// RangeFunc visits items in order and stops the traversal if it returns false.
+type RangeFunc[T any] func(int, T) bool
+
+// PushFront inserts the item at the head of the queue. When the queue is full, the result
+// depends on the FullPolicy of the queue, and PolicyOverwrite drops the newest item.
+func (q *CircularQueue[T]) PushFront(item T) bool {
+	if q.IsFull() {
+		switch q.policy {
+		case PolicyGrow:
+			q.grow()
+		case PolicyOverwrite:
+			q.PopBack()
+		default:
+			return false
+		}
+	}
+	q.head = (q.head - 1 + q.size + 1) % (q.size + 1)
+	q.data[q.head] = item
+	return true
+}
+
+// PushBack is an alias of EnQueue.
+func (q *CircularQueue[T]) PushBack(item T) bool {
+	return q.EnQueue(item)
+}
+
+// PopFront is an alias of DeQueue.
+func (q *CircularQueue[T]) PopFront() (T, bool) {
+	return q.DeQueue()
+}
+
+// PopBack removes and returns the newest item.
+func (q *CircularQueue[T]) PopBack() (T, bool) {
+	var zero T
+	if q.IsEmpty() {
+		return zero, false
+	}
+	q.tail = (q.tail - 1 + q.size + 1) % (q.size + 1)
+	item := q.data[q.tail]
+	q.data[q.tail] = zero
+	return item, true
+}
+
+// At returns the i-th item counting from the head, where 0 is the oldest one.
+func (q *CircularQueue[T]) At(i int) (T, bool) {
+	if i < 0 || i >= q.CountUsed() {
+		var zero T
+		return zero, false
+	}
+	return q.data[q.index(i)], true
+}
+
+// Set replaces the i-th item counting from the head.
+func (q *CircularQueue[T]) Set(i int, item T) bool {
+	if i < 0 || i >= q.CountUsed() {
+		return false
+	}
+	q.data[q.index(i)] = item
+	return true
+}
+
+// Rotate moves k items from the head to the tail, or -k items from the tail to the head
+// if k is negative. After Rotate(k), the item previously at At(k) will be at At(0).
+func (q *CircularQueue[T]) Rotate(k int) {
+	n := q.CountUsed()
+	if n == 0 {
+		return
+	}
+	if k %= n; k < 0 {
+		k += n
+	}
+	// Move the shorter side.
+	if k <= n-k {
+		for ; k > 0; k-- {
+			item, _ := q.DeQueue()
+			q.data[q.tail] = item
+			q.tail = (q.tail + 1) % (q.size + 1)
+		}
+	} else {
+		for k = n - k; k > 0; k-- {
+			item, _ := q.PopBack()
+			q.head = (q.head - 1 + q.size + 1) % (q.size + 1)
+			q.data[q.head] = item
+		}
+	}
+}
+
+// Range traverses the items from the oldest to the newest without dequeuing them.
+func (q *CircularQueue[T]) Range(f RangeFunc[T]) {
+	for i, n := 0, q.CountUsed(); i < n; i++ {
+		if !f(i, q.data[q.index(i)]) {
+			return
+		}
+	}
+}
+
+// ReverseRange traverses the items from the newest to the oldest without dequeuing them.
+func (q *CircularQueue[T]) ReverseRange(f RangeFunc[T]) {
+	for i := q.CountUsed() - 1; i >= 0; i-- {
+		if !f(i, q.data[q.index(i)]) {
+			return
+		}
+	}
+}
+
+// Slice returns a copy of the items from the oldest to the newest.
+func (q *CircularQueue[T]) Slice() []T {
+	n := q.CountUsed()
+	ret := make([]T, n)
+	if q.head+n <= len(q.data) {
+		copy(ret, q.data[q.head:q.head+n])
+	} else {
+		m := copy(ret, q.data[q.head:])
+		copy(ret[m:], q.data[:n-m])
+	}
+	return ret
+}
+
+// index returns the slot of the i-th item counting from the head.
+func (q *CircularQueue[T]) index(i int) int {
+	return (q.head + i) % (q.size + 1)
+}
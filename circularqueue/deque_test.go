@@ -0,0 +1,136 @@
+package circularqueue
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCircularQueue_Deque(t *testing.T) {
+	q := NewCircularQueue[int](4)
+	q.EnQueue(2)
+	q.EnQueue(3)
+	q.PushFront(1)
+	q.PushFront(0)
+	if ok := q.PushFront(-1); ok {
+		t.Errorf("PushFront() = %v, want %v", ok, false)
+	}
+	if got, want := q.Slice(), []int{0, 1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Slice() = %v, want %v", got, want)
+	}
+	if item, ok := q.PopBack(); !ok || item != 3 {
+		t.Errorf("PopBack() = %v, %v, want %v, %v", item, ok, 3, true)
+	}
+	if item, ok := q.PopFront(); !ok || item != 0 {
+		t.Errorf("PopFront() = %v, %v, want %v, %v", item, ok, 0, true)
+	}
+	if got, want := q.Slice(), []int{1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Slice() = %v, want %v", got, want)
+	}
+	q.PopBack()
+	q.PopBack()
+	if item, ok := q.PopBack(); ok {
+		t.Errorf("PopBack() = %v, %v, want %v, %v", item, ok, 0, false)
+	}
+}
+
+func TestCircularQueue_PushFrontPolicy(t *testing.T) {
+	q := NewCircularQueueWithPolicy[int](2, PolicyOverwrite)
+	for i := 0; i < 4; i++ {
+		q.PushFront(i)
+	}
+	if got, want := q.Slice(), []int{3, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Slice() = %v, want %v", got, want)
+	}
+
+	q = NewCircularQueueWithPolicy[int](2, PolicyGrow)
+	for i := 0; i < 4; i++ {
+		q.PushFront(i)
+	}
+	if got, want := q.Slice(), []int{3, 2, 1, 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Slice() = %v, want %v", got, want)
+	}
+}
+
+func TestCircularQueue_AtSet(t *testing.T) {
+	q := NewCircularQueue[int](3)
+	for i := 0; i < 5; i++ {
+		q.EnQueue(i)
+		if i >= 2 {
+			q.DeQueue()
+		}
+	}
+	// Items are 3, 4 and the ring has wrapped around.
+	if item, ok := q.At(1); !ok || item != 4 {
+		t.Errorf("At(1) = %v, %v, want %v, %v", item, ok, 4, true)
+	}
+	if _, ok := q.At(2); ok {
+		t.Errorf("At(2) should be out of range")
+	}
+	if ok := q.Set(0, 30); !ok {
+		t.Errorf("Set(0) = %v, want %v", ok, true)
+	}
+	if ok := q.Set(-1, 0); ok {
+		t.Errorf("Set(-1) = %v, want %v", ok, false)
+	}
+	if item := q.Front(); item != 30 {
+		t.Errorf("Front() = %v, want %v", item, 30)
+	}
+}
+
+func TestCircularQueue_Rotate(t *testing.T) {
+	tests := []struct {
+		name string
+		k    int
+		want []int
+	}{
+		{name: "zero", k: 0, want: []int{0, 1, 2, 3, 4}},
+		{name: "left", k: 1, want: []int{1, 2, 3, 4, 0}},
+		{name: "left more than half", k: 4, want: []int{4, 0, 1, 2, 3}},
+		{name: "right", k: -2, want: []int{3, 4, 0, 1, 2}},
+		{name: "overflow", k: 7, want: []int{2, 3, 4, 0, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewCircularQueue[int](5)
+			for i := 0; i < 5; i++ {
+				q.EnQueue(i)
+			}
+			q.Rotate(tt.k)
+			if got := q.Slice(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Rotate(%v) = %v, want %v", tt.k, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCircularQueue_Range(t *testing.T) {
+	q := NewCircularQueue[int](4)
+	for i := 0; i < 6; i++ {
+		q.EnQueue(i)
+		if i >= 3 {
+			q.DeQueue()
+		}
+	}
+
+	var got []int
+	q.Range(func(i, item int) bool {
+		got = append(got, item)
+		return true
+	})
+	if want := []int{3, 4, 5}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Range() = %v, want %v", got, want)
+	}
+
+	got = got[:0]
+	q.ReverseRange(func(i, item int) bool {
+		got = append(got, item)
+		return i > 1
+	})
+	if want := []int{5, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ReverseRange() = %v, want %v", got, want)
+	}
+
+	if q.CountUsed() != 3 {
+		t.Errorf("CountUsed() = %v, want %v", q.CountUsed(), 3)
+	}
+}
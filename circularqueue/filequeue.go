@@ -0,0 +1,253 @@
+package circularqueue
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+)
+
+// SyncPolicy defines when FileQueue flushes its writes to the disk.
+type SyncPolicy int
+
+const (
+	// SyncManual only fsyncs on explicit Sync() or Close().
+	SyncManual SyncPolicy = iota
+	// SyncAlways fsyncs after every EnQueue and DeQueue, so that the queue survives a power
+	// loss as well as a process crash.
+	SyncAlways
+)
+
+var (
+	ErrPayloadTooLarge = errors.New("circularqueue: payload exceeds slot size")
+	ErrLayoutMismatch  = errors.New("circularqueue: file layout mismatch")
+)
+
+// The file starts with a fixed-size header followed by size+1 slots. Each slot stores a
+// 4-byte payload length followed by slotSize bytes of payload.
+const (
+	fileQueueMagic      = "CQUEUE01"
+	fileQueueHeaderSize = 40
+	slotLengthSize      = 4
+)
+
+// FileQueue is a CircularQueue of []byte payloads backed by a file, so that the queued items
+// survive a crash. The head and tail are stored in the file header and restored on reopen.
+// FileQueue is not safe for concurrent use.
+type FileQueue struct {
+	head     int
+	tail     int
+	size     int
+	slotSize int
+	policy   SyncPolicy
+	f        *os.File
+}
+
+// OpenFileQueue opens the queue stored at path, or creates it if the file does not exist.
+// An existing file must have been created with the same size and slotSize.
+func OpenFileQueue(path string, size, slotSize int, policy SyncPolicy) (*FileQueue, error) {
+	if size <= 0 {
+		size = defaultCircularQueueSize
+	}
+	if slotSize <= 0 {
+		return nil, ErrLayoutMismatch
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	q := &FileQueue{
+		size:     size,
+		slotSize: slotSize,
+		policy:   policy,
+		f:        f,
+	}
+	if err := q.load(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return q, nil
+}
+
+func (q *FileQueue) Size() int {
+	return q.size
+}
+
+func (q *FileQueue) SlotSize() int {
+	return q.slotSize
+}
+
+func (q *FileQueue) CountUsed() int {
+	if q.tail >= q.head {
+		return q.tail - q.head
+	}
+	return q.tail + q.size + 1 - q.head
+}
+
+func (q *FileQueue) CountUnused() int {
+	return q.Size() - q.CountUsed()
+}
+
+func (q *FileQueue) IsEmpty() bool {
+	return q.tail == q.head
+}
+
+func (q *FileQueue) IsFull() bool {
+	return (q.tail+1)%(q.size+1) == q.head
+}
+
+// EnQueue appends the payload to the tail. Returns false if the queue is full.
+func (q *FileQueue) EnQueue(item []byte) (bool, error) {
+	if len(item) > q.slotSize {
+		return false, ErrPayloadTooLarge
+	}
+	if q.IsFull() {
+		return false, nil
+	}
+	buf := make([]byte, slotLengthSize+len(item))
+	binary.LittleEndian.PutUint32(buf, uint32(len(item)))
+	copy(buf[slotLengthSize:], item)
+	// The slot must be written before the header, so that a crash in between loses the
+	// item instead of exposing a garbage slot. Under SyncAlways the slot is also synced on
+	// its own, otherwise the header may reach the disk before the slot on a power loss.
+	if _, err := q.f.WriteAt(buf, q.slotOffset(q.tail)); err != nil {
+		return false, err
+	}
+	if q.policy == SyncAlways {
+		if err := q.f.Sync(); err != nil {
+			return false, err
+		}
+	}
+	// The tail only moves on after the header is committed, so that a failed commit leaves
+	// the queue as it was.
+	tail := (q.tail + 1) % (q.size + 1)
+	if err := q.commit(q.head, tail); err != nil {
+		return false, err
+	}
+	q.tail = tail
+	return true, nil
+}
+
+// DeQueue removes and returns the payload at the head.
+func (q *FileQueue) DeQueue() ([]byte, bool, error) {
+	item, ok, err := q.Front()
+	if !ok || err != nil {
+		return nil, ok, err
+	}
+	head := (q.head + 1) % (q.size + 1)
+	if err := q.commit(head, q.tail); err != nil {
+		return nil, false, err
+	}
+	q.head = head
+	return item, true, nil
+}
+
+// Front returns the payload at the head without removing it.
+func (q *FileQueue) Front() ([]byte, bool, error) {
+	if q.IsEmpty() {
+		return nil, false, nil
+	}
+	item, err := q.readSlot(q.head)
+	if err != nil {
+		return nil, false, err
+	}
+	return item, true, nil
+}
+
+// Rear returns the payload at the tail without removing it.
+func (q *FileQueue) Rear() ([]byte, bool, error) {
+	if q.IsEmpty() {
+		return nil, false, nil
+	}
+	item, err := q.readSlot((q.tail - 1 + q.size + 1) % (q.size + 1))
+	if err != nil {
+		return nil, false, err
+	}
+	return item, true, nil
+}
+
+// Sync flushes all the writes to the disk.
+func (q *FileQueue) Sync() error {
+	return q.f.Sync()
+}
+
+// Close syncs and closes the underlying file.
+func (q *FileQueue) Close() error {
+	if err := q.f.Sync(); err != nil {
+		q.f.Close()
+		return err
+	}
+	return q.f.Close()
+}
+
+func (q *FileQueue) slotOffset(i int) int64 {
+	return fileQueueHeaderSize + int64(i)*int64(slotLengthSize+q.slotSize)
+}
+
+func (q *FileQueue) readSlot(i int) ([]byte, error) {
+	var length [slotLengthSize]byte
+	if _, err := q.f.ReadAt(length[:], q.slotOffset(i)); err != nil {
+		return nil, err
+	}
+	n := int(binary.LittleEndian.Uint32(length[:]))
+	if n > q.slotSize {
+		return nil, ErrLayoutMismatch
+	}
+	item := make([]byte, n)
+	if _, err := q.f.ReadAt(item, q.slotOffset(i)+slotLengthSize); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+func (q *FileQueue) encodeHeader(head, tail int) []byte {
+	buf := make([]byte, fileQueueHeaderSize)
+	copy(buf, fileQueueMagic)
+	binary.LittleEndian.PutUint64(buf[8:], uint64(q.size))
+	binary.LittleEndian.PutUint64(buf[16:], uint64(q.slotSize))
+	binary.LittleEndian.PutUint64(buf[24:], uint64(head))
+	binary.LittleEndian.PutUint64(buf[32:], uint64(tail))
+	return buf
+}
+
+// commit writes the header with the given head and tail, and syncs according to the
+// SyncPolicy.
+func (q *FileQueue) commit(head, tail int) error {
+	if _, err := q.f.WriteAt(q.encodeHeader(head, tail), 0); err != nil {
+		return err
+	}
+	if q.policy == SyncAlways {
+		return q.f.Sync()
+	}
+	return nil
+}
+
+// load restores head and tail from an existing file, or initializes a new one.
+func (q *FileQueue) load() error {
+	buf := make([]byte, fileQueueHeaderSize)
+	n, err := q.f.ReadAt(buf, 0)
+	if n == 0 && err == io.EOF {
+		if err := q.f.Truncate(q.slotOffset(q.size + 1)); err != nil {
+			return err
+		}
+		return q.commit(q.head, q.tail)
+	}
+	if err == io.EOF {
+		// The file is shorter than a header.
+		return ErrLayoutMismatch
+	}
+	if err != nil {
+		return err
+	}
+	if string(buf[:8]) != fileQueueMagic ||
+		binary.LittleEndian.Uint64(buf[8:]) != uint64(q.size) ||
+		binary.LittleEndian.Uint64(buf[16:]) != uint64(q.slotSize) {
+		return ErrLayoutMismatch
+	}
+	head, tail := binary.LittleEndian.Uint64(buf[24:]), binary.LittleEndian.Uint64(buf[32:])
+	if head > uint64(q.size) || tail > uint64(q.size) {
+		return ErrLayoutMismatch
+	}
+	q.head, q.tail = int(head), int(tail)
+	return nil
+}
@@ -0,0 +1,125 @@
+package circularqueue
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestFileQueue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue")
+	q, err := OpenFileQueue(path, 3, 8, SyncAlways)
+	if err != nil {
+		t.Fatalf("OpenFileQueue() = %v", err)
+	}
+	if !q.IsEmpty() || q.Size() != 3 {
+		t.Errorf("IsEmpty() = %v, Size() = %v, want %v, %v", q.IsEmpty(), q.Size(), true, 3)
+	}
+	if _, err := q.EnQueue([]byte("too long payload")); !errors.Is(err, ErrPayloadTooLarge) {
+		t.Errorf("EnQueue() = %v, want %v", err, ErrPayloadTooLarge)
+	}
+	// Wrap around the ring before checking the contents.
+	for i := 0; i < 5; i++ {
+		if ok, err := q.EnQueue([]byte("item" + strconv.Itoa(i))); !ok || err != nil {
+			t.Errorf("EnQueue() = %v, %v, want %v, nil", ok, err, true)
+		}
+		if i < 2 {
+			q.DeQueue()
+		}
+	}
+	if ok, err := q.EnQueue([]byte("full")); ok || err != nil {
+		t.Errorf("EnQueue() on full queue = %v, %v, want %v, nil", ok, err, false)
+	}
+	if item, ok, _ := q.Rear(); !ok || string(item) != "item4" {
+		t.Errorf("Rear() = %s, %v, want %v, %v", item, ok, "item4", true)
+	}
+	if item, ok, err := q.DeQueue(); !ok || err != nil || string(item) != "item2" {
+		t.Errorf("DeQueue() = %s, %v, %v, want %v, %v, nil", item, ok, err, "item2", true)
+	}
+	if err := q.Close(); err != nil {
+		t.Fatalf("Close() = %v", err)
+	}
+
+	// Reopen and restore the state.
+	q, err = OpenFileQueue(path, 3, 8, SyncManual)
+	if err != nil {
+		t.Fatalf("OpenFileQueue() = %v", err)
+	}
+	defer q.Close()
+	if q.CountUsed() != 2 {
+		t.Errorf("CountUsed() = %v, want %v", q.CountUsed(), 2)
+	}
+	for _, want := range []string{"item3", "item4"} {
+		if item, ok, err := q.DeQueue(); !ok || err != nil || string(item) != want {
+			t.Errorf("DeQueue() = %s, %v, %v, want %v, %v, nil", item, ok, err, want, true)
+		}
+	}
+	if _, ok, err := q.DeQueue(); ok || err != nil {
+		t.Errorf("DeQueue() on empty queue = %v, %v, want %v, nil", ok, err, false)
+	}
+	if err := q.Sync(); err != nil {
+		t.Errorf("Sync() = %v", err)
+	}
+}
+
+func TestFileQueue_LayoutMismatch(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "queue")
+	q, err := OpenFileQueue(path, 3, 8, SyncManual)
+	if err != nil {
+		t.Fatalf("OpenFileQueue() = %v", err)
+	}
+	q.Close()
+
+	if _, err := OpenFileQueue(path, 4, 8, SyncManual); !errors.Is(err, ErrLayoutMismatch) {
+		t.Errorf("OpenFileQueue() with another size = %v, want %v", err, ErrLayoutMismatch)
+	}
+
+	garbage := filepath.Join(dir, "garbage")
+	if err := os.WriteFile(garbage, make([]byte, fileQueueHeaderSize), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenFileQueue(garbage, 3, 8, SyncManual); !errors.Is(err, ErrLayoutMismatch) {
+		t.Errorf("OpenFileQueue() on garbage = %v, want %v", err, ErrLayoutMismatch)
+	}
+
+	truncated := filepath.Join(dir, "truncated")
+	if err := os.WriteFile(truncated, []byte(fileQueueMagic), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenFileQueue(truncated, 3, 8, SyncManual); !errors.Is(err, ErrLayoutMismatch) {
+		t.Errorf("OpenFileQueue() on truncated header = %v, want %v", err, ErrLayoutMismatch)
+	}
+}
+
+func TestFileQueue_CommitFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue")
+	q, err := OpenFileQueue(path, 3, 8, SyncManual)
+	if err != nil {
+		t.Fatalf("OpenFileQueue() = %v", err)
+	}
+	defer q.Close()
+	q.EnQueue([]byte("item"))
+
+	// Reads still work on a read-only file, but the header can not be committed.
+	rw := q.f
+	if q.f, err = os.Open(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := q.DeQueue(); err == nil {
+		t.Errorf("DeQueue() on read-only file should fail")
+	}
+	if _, err := q.EnQueue([]byte("next")); err == nil {
+		t.Errorf("EnQueue() on read-only file should fail")
+	}
+	if q.CountUsed() != 1 {
+		t.Errorf("CountUsed() = %v, want %v", q.CountUsed(), 1)
+	}
+	q.f.Close()
+	q.f = rw
+	if item, ok, err := q.DeQueue(); !ok || err != nil || string(item) != "item" {
+		t.Errorf("DeQueue() = %s, %v, %v, want %v, %v, nil", item, ok, err, "item", true)
+	}
+}
@@ -0,0 +1,168 @@
+package circularqueue
+
+import "sync/atomic"
+
+const cacheLineSize = 64
+
+// cacheLinePad prevents false sharing between the fields that are written by different goroutines.
+type cacheLinePad [cacheLineSize]byte
+
+// roundUpPowerOfTwo returns the smallest power of two that is not less than size.
+func roundUpPowerOfTwo(size int) int {
+	if size <= 0 {
+		size = defaultCircularQueueSize
+	}
+	n := 1
+	for n < size {
+		n <<= 1
+	}
+	return n
+}
+
+// SPSC is a lock-free ring buffer for exactly one producer goroutine and one consumer goroutine.
+// Its capacity is rounded up to a power of two.
+type SPSC[T any] struct {
+	_    cacheLinePad
+	head atomic.Uint64 // head is only written by the consumer.
+	_    cacheLinePad
+	tail atomic.Uint64 // tail is only written by the producer.
+	_    cacheLinePad
+	mask uint64
+	data []T
+}
+
+func NewSPSC[T any](size int) *SPSC[T] {
+	size = roundUpPowerOfTwo(size)
+	return &SPSC[T]{
+		mask: uint64(size - 1),
+		data: make([]T, size),
+	}
+}
+
+func (q *SPSC[T]) Size() int {
+	return len(q.data)
+}
+
+// CountUsed returns the number of items in the ring. It is only a snapshot when the ring is
+// being used concurrently.
+func (q *SPSC[T]) CountUsed() int {
+	head := q.head.Load()
+	return int(q.tail.Load() - head)
+}
+
+// EnQueue must only be called by the producer.
+func (q *SPSC[T]) EnQueue(item T) bool {
+	tail := q.tail.Load()
+	if tail-q.head.Load() == uint64(len(q.data)) {
+		return false
+	}
+	q.data[tail&q.mask] = item
+	q.tail.Store(tail + 1)
+	return true
+}
+
+// DeQueue must only be called by the consumer.
+func (q *SPSC[T]) DeQueue() (T, bool) {
+	var zero T
+	head := q.head.Load()
+	if head == q.tail.Load() {
+		return zero, false
+	}
+	item := q.data[head&q.mask]
+	q.data[head&q.mask] = zero
+	q.head.Store(head + 1)
+	return item, true
+}
+
+type mpmcSlot[T any] struct {
+	// seq equals the position that may be written next into the slot, or position+1 once
+	// the slot has been written and is ready to be read.
+	seq  atomic.Uint64
+	item T
+}
+
+// MPMC is a lock-free ring buffer for multiple producers and multiple consumers, which uses
+// per-slot sequence numbers as described by Dmitry Vyukov.
+// Its capacity is rounded up to a power of two.
+type MPMC[T any] struct {
+	_     cacheLinePad
+	head  atomic.Uint64
+	_     cacheLinePad
+	tail  atomic.Uint64
+	_     cacheLinePad
+	mask  uint64
+	slots []mpmcSlot[T]
+}
+
+func NewMPMC[T any](size int) *MPMC[T] {
+	size = roundUpPowerOfTwo(size)
+	q := &MPMC[T]{
+		mask:  uint64(size - 1),
+		slots: make([]mpmcSlot[T], size),
+	}
+	for i := range q.slots {
+		q.slots[i].seq.Store(uint64(i))
+	}
+	return q
+}
+
+func (q *MPMC[T]) Size() int {
+	return len(q.slots)
+}
+
+// CountUsed returns the number of items in the ring. It is only a snapshot when the ring is
+// being used concurrently.
+func (q *MPMC[T]) CountUsed() int {
+	head := q.head.Load()
+	tail := q.tail.Load()
+	if tail < head {
+		return 0
+	}
+	return int(tail - head)
+}
+
+func (q *MPMC[T]) EnQueue(item T) bool {
+	pos := q.tail.Load()
+	for {
+		slot := &q.slots[pos&q.mask]
+		seq := slot.seq.Load()
+		switch diff := int64(seq - pos); {
+		case diff == 0:
+			if q.tail.CompareAndSwap(pos, pos+1) {
+				slot.item = item
+				slot.seq.Store(pos + 1)
+				return true
+			}
+			pos = q.tail.Load()
+		case diff < 0:
+			// The slot has not been read since the previous lap, so the ring is full.
+			return false
+		default:
+			pos = q.tail.Load()
+		}
+	}
+}
+
+func (q *MPMC[T]) DeQueue() (T, bool) {
+	var zero T
+	pos := q.head.Load()
+	for {
+		slot := &q.slots[pos&q.mask]
+		seq := slot.seq.Load()
+		switch diff := int64(seq - (pos + 1)); {
+		case diff == 0:
+			if q.head.CompareAndSwap(pos, pos+1) {
+				item := slot.item
+				slot.item = zero
+				slot.seq.Store(pos + q.mask + 1)
+				return item, true
+			}
+			pos = q.head.Load()
+		case diff < 0:
+			// The slot has not been written in this lap, so the ring is empty.
+			return zero, false
+		default:
+			pos = q.head.Load()
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package circularqueue
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+)
+
+type ring interface {
+	Size() int
+	CountUsed() int
+	EnQueue(int) bool
+	DeQueue() (int, bool)
+}
+
+func TestLockFree_Sequential(t *testing.T) {
+	for name, q := range map[string]ring{"spsc": NewSPSC[int](5), "mpmc": NewMPMC[int](5)} {
+		t.Run(name, func(t *testing.T) {
+			if q.Size() != 8 {
+				t.Errorf("Size() = %v, want %v", q.Size(), 8)
+			}
+			for lap := 0; lap < 3; lap++ {
+				for i := 0; i < 8; i++ {
+					if !q.EnQueue(i) {
+						t.Errorf("EnQueue(%v) = %v, want %v", i, false, true)
+					}
+				}
+				if q.EnQueue(8) {
+					t.Errorf("EnQueue() on full ring should fail")
+				}
+				if q.CountUsed() != 8 {
+					t.Errorf("CountUsed() = %v, want %v", q.CountUsed(), 8)
+				}
+				for i := 0; i < 8; i++ {
+					if item, ok := q.DeQueue(); !ok || item != i {
+						t.Errorf("DeQueue() = %v, %v, want %v, %v", item, ok, i, true)
+					}
+				}
+				if _, ok := q.DeQueue(); ok {
+					t.Errorf("DeQueue() on empty ring should fail")
+				}
+			}
+		})
+	}
+}
+
+func TestSPSC_Concurrent(t *testing.T) {
+	const n = 10000
+	q := NewSPSC[int](16)
+	go func() {
+		for i := 0; i < n; {
+			if q.EnQueue(i) {
+				i++
+			} else {
+				runtime.Gosched()
+			}
+		}
+	}()
+	for i := 0; i < n; {
+		if item, ok := q.DeQueue(); ok {
+			if item != i {
+				t.Fatalf("DeQueue() = %v, want %v", item, i)
+			}
+			i++
+		} else {
+			runtime.Gosched()
+		}
+	}
+}
+
+func TestMPMC_Concurrent(t *testing.T) {
+	const producers, consumers, n = 4, 4, 5000
+	q := NewMPMC[int](64)
+
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < n; {
+				if q.EnQueue(p*n + i) {
+					i++
+				} else {
+					runtime.Gosched()
+				}
+			}
+		}(p)
+	}
+
+	seen := make([][]bool, consumers)
+	var cwg sync.WaitGroup
+	for c := 0; c < consumers; c++ {
+		seen[c] = make([]bool, producers*n)
+		cwg.Add(1)
+		go func(c int) {
+			defer cwg.Done()
+			for i := 0; i < n; {
+				if item, ok := q.DeQueue(); ok {
+					seen[c][item] = true
+					i++
+				} else {
+					runtime.Gosched()
+				}
+			}
+		}(c)
+	}
+	wg.Wait()
+	cwg.Wait()
+
+	for i := 0; i < producers*n; i++ {
+		count := 0
+		for c := 0; c < consumers; c++ {
+			if seen[c][i] {
+				count++
+			}
+		}
+		if count != 1 {
+			t.Fatalf("Item %v was consumed %v times", i, count)
+		}
+	}
+}
+
+func BenchmarkSPSC(b *testing.B) {
+	q := NewSPSC[int](1024)
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < b.N; {
+			if _, ok := q.DeQueue(); ok {
+				i++
+			} else {
+				runtime.Gosched()
+			}
+		}
+		close(done)
+	}()
+	for i := 0; i < b.N; {
+		if q.EnQueue(i) {
+			i++
+		} else {
+			runtime.Gosched()
+		}
+	}
+	<-done
+}
+
+func BenchmarkSPSCChannel(b *testing.B) {
+	ch := make(chan int, 1024)
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < b.N; i++ {
+			<-ch
+		}
+		close(done)
+	}()
+	for i := 0; i < b.N; i++ {
+		ch <- i
+	}
+	<-done
+}
+
+func BenchmarkMPMC(b *testing.B) {
+	q := NewMPMC[int](1024)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			for !q.EnQueue(1) {
+				runtime.Gosched()
+			}
+			for {
+				if _, ok := q.DeQueue(); ok {
+					break
+				}
+				runtime.Gosched()
+			}
+		}
+	})
+}
+
+func BenchmarkMPMCChannel(b *testing.B) {
+	ch := make(chan int, 1024)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			ch <- 1
+			<-ch
+		}
+	})
+}
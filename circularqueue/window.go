@@ -0,0 +1,169 @@
+package circularqueue
+
+import (
+	"math"
+	"time"
+)
+
+type sample struct {
+	seq uint64
+	val float64
+	at  time.Time
+}
+
+// Window keeps the last Size() samples and maintains their aggregates incrementally:
+// sum, mean and variance in O(1), min and max in O(1) amortized by monotonic deques.
+// If maxAge is set, samples older than maxAge are evicted as well.
+// Window is not safe for concurrent use.
+type Window struct {
+	samples    *CircularQueue[sample]
+	minq, maxq *CircularQueue[sample]
+	seq        uint64
+
+	// mean and m2 are maintained by Welford's algorithm.
+	sum, mean, m2 float64
+
+	maxAge time.Duration
+	now    func() time.Time
+}
+
+// NewWindow returns a Window that keeps the last `size` samples.
+func NewWindow(size int) *Window {
+	return NewTimeWindow(size, 0, nil)
+}
+
+// NewTimeWindow returns a Window that keeps at most `size` samples, none of which is older
+// than maxAge according to `now`. A nil `now` means time.Now.
+func NewTimeWindow(size int, maxAge time.Duration, now func() time.Time) *Window {
+	if now == nil {
+		now = time.Now
+	}
+	return &Window{
+		samples: NewCircularQueue[sample](size),
+		minq:    NewCircularQueue[sample](size),
+		maxq:    NewCircularQueue[sample](size),
+		maxAge:  maxAge,
+		now:     now,
+	}
+}
+
+func (w *Window) Size() int {
+	return w.samples.Size()
+}
+
+func (w *Window) Len() int {
+	w.Evict()
+	return w.samples.CountUsed()
+}
+
+// Push adds a sample and evicts the oldest one if the window is full.
+func (w *Window) Push(v float64) {
+	w.Evict()
+	if w.samples.IsFull() {
+		w.pop()
+	}
+	w.seq++
+	s := sample{seq: w.seq, val: v}
+	if w.maxAge > 0 {
+		s.at = w.now()
+	}
+	w.samples.EnQueue(s)
+
+	for !w.minq.IsEmpty() && w.minq.Rear().val >= v {
+		w.minq.PopBack()
+	}
+	w.minq.PushBack(s)
+	for !w.maxq.IsEmpty() && w.maxq.Rear().val <= v {
+		w.maxq.PopBack()
+	}
+	w.maxq.PushBack(s)
+
+	n := float64(w.samples.CountUsed())
+	delta := v - w.mean
+	w.sum += v
+	w.mean += delta / n
+	w.m2 += delta * (v - w.mean)
+}
+
+// Evict drops the samples older than maxAge. It is a no-op if maxAge is not set.
+func (w *Window) Evict() {
+	if w.maxAge <= 0 {
+		return
+	}
+	deadline := w.now().Add(-w.maxAge)
+	for !w.samples.IsEmpty() && w.samples.Front().at.Before(deadline) {
+		w.pop()
+	}
+}
+
+func (w *Window) Sum() float64 {
+	w.Evict()
+	return w.sum
+}
+
+// Mean returns the mean of the samples, or NaN if the window is empty.
+func (w *Window) Mean() float64 {
+	if w.Len() == 0 {
+		return math.NaN()
+	}
+	return w.mean
+}
+
+// Variance returns the population variance of the samples, or NaN if the window is empty.
+func (w *Window) Variance() float64 {
+	n := w.Len()
+	if n == 0 {
+		return math.NaN()
+	}
+	// Guard against small negative values caused by rounding.
+	return math.Max(w.m2, 0) / float64(n)
+}
+
+func (w *Window) Min() (float64, bool) {
+	if w.Len() == 0 {
+		return 0, false
+	}
+	return w.minq.Front().val, true
+}
+
+func (w *Window) Max() (float64, bool) {
+	if w.Len() == 0 {
+		return 0, false
+	}
+	return w.maxq.Front().val, true
+}
+
+// Slice returns a copy of the samples from the oldest to the newest.
+func (w *Window) Slice() []float64 {
+	w.Evict()
+	ret := make([]float64, 0, w.samples.CountUsed())
+	w.samples.Range(func(_ int, s sample) bool {
+		ret = append(ret, s.val)
+		return true
+	})
+	return ret
+}
+
+// pop removes the oldest sample and updates all the aggregates.
+func (w *Window) pop() {
+	s, ok := w.samples.DeQueue()
+	if !ok {
+		return
+	}
+	if w.minq.Front().seq == s.seq {
+		w.minq.DeQueue()
+	}
+	if w.maxq.Front().seq == s.seq {
+		w.maxq.DeQueue()
+	}
+
+	n := w.samples.CountUsed()
+	if n == 0 {
+		w.sum, w.mean, w.m2 = 0, 0, 0
+		return
+	}
+	delta := s.val - w.mean
+	w.sum -= s.val
+	w.mean -= delta / float64(n)
+	w.m2 -= delta * (s.val - w.mean)
+}
@@ -0,0 +1,95 @@
+package circularqueue
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestWindow(t *testing.T) {
+	w := NewWindow(3)
+	if _, ok := w.Min(); ok {
+		t.Errorf("Min() on empty window should fail")
+	}
+	if !math.IsNaN(w.Mean()) {
+		t.Errorf("Mean() on empty window = %v, want NaN", w.Mean())
+	}
+
+	tests := []struct {
+		push     float64
+		sum      float64
+		mean     float64
+		variance float64
+		min, max float64
+	}{
+		{push: 4, sum: 4, mean: 4, variance: 0, min: 4, max: 4},
+		{push: 2, sum: 6, mean: 3, variance: 1, min: 2, max: 4},
+		{push: 6, sum: 12, mean: 4, variance: 8.0 / 3, min: 2, max: 6},
+		{push: 1, sum: 9, mean: 3, variance: 14.0 / 3, min: 1, max: 6},
+		{push: 5, sum: 12, mean: 4, variance: 14.0 / 3, min: 1, max: 6},
+		{push: 3, sum: 9, mean: 3, variance: 8.0 / 3, min: 1, max: 5},
+		{push: 3, sum: 11, mean: 11.0 / 3, variance: 8.0 / 9, min: 3, max: 5},
+	}
+	for i, tt := range tests {
+		w.Push(tt.push)
+		if !almostEqual(w.Sum(), tt.sum) {
+			t.Errorf("Step %v: Sum() = %v, want %v", i, w.Sum(), tt.sum)
+		}
+		if !almostEqual(w.Mean(), tt.mean) {
+			t.Errorf("Step %v: Mean() = %v, want %v", i, w.Mean(), tt.mean)
+		}
+		if !almostEqual(w.Variance(), tt.variance) {
+			t.Errorf("Step %v: Variance() = %v, want %v", i, w.Variance(), tt.variance)
+		}
+		if got, _ := w.Min(); got != tt.min {
+			t.Errorf("Step %v: Min() = %v, want %v", i, got, tt.min)
+		}
+		if got, _ := w.Max(); got != tt.max {
+			t.Errorf("Step %v: Max() = %v, want %v", i, got, tt.max)
+		}
+	}
+	if got, want := w.Slice(), []float64{5, 3, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Slice() = %v, want %v", got, want)
+	}
+}
+
+func TestTimeWindow(t *testing.T) {
+	now := time.Unix(0, 0)
+	w := NewTimeWindow(10, time.Minute, func() time.Time { return now })
+
+	w.Push(10)
+	now = now.Add(30 * time.Second)
+	w.Push(1)
+	now = now.Add(20 * time.Second)
+	w.Push(5)
+	if w.Len() != 3 {
+		t.Errorf("Len() = %v, want %v", w.Len(), 3)
+	}
+	if got, _ := w.Max(); got != 10 {
+		t.Errorf("Max() = %v, want %v", got, 10)
+	}
+
+	now = now.Add(20 * time.Second)
+	if w.Len() != 2 {
+		t.Errorf("Len() = %v, want %v", w.Len(), 2)
+	}
+	if got, _ := w.Max(); got != 5 {
+		t.Errorf("Max() = %v, want %v", got, 5)
+	}
+	if !almostEqual(w.Mean(), 3) {
+		t.Errorf("Mean() = %v, want %v", w.Mean(), 3)
+	}
+
+	now = now.Add(time.Hour)
+	if w.Len() != 0 || w.Sum() != 0 {
+		t.Errorf("Len() = %v, Sum() = %v, want empty window", w.Len(), w.Sum())
+	}
+	if _, ok := w.Min(); ok {
+		t.Errorf("Min() on expired window should fail")
+	}
+}
@@ -0,0 +1,183 @@
+package dsu
+
+import "sync"
+
+// pnode is a node of the persistent segment tree that backs Persistent. Only the leaves
+// carry meaningful parent/rank/size values.
+type pnode struct {
+	lchild, rchild int
+	parent         int64
+	rank           int64
+	size           int64
+}
+
+// Persistent is a versioned DSU. Every call to Merge produces a new version, and all the
+// previous versions stay queryable through the `*At` methods.
+// It uses union-by-rank without path compression on top of a persistent array, so each
+// query costs O(log^2 n).
+type Persistent struct {
+	n     int64
+	nodes []pnode
+	roots []int
+	sccs  []int64
+	mu    sync.RWMutex
+}
+
+var _ DSU = &Persistent{}
+
+func NewPersistent(n int64) *Persistent {
+	u := &Persistent{
+		n:     n,
+		nodes: make([]pnode, 1, 2*(n+1)+1),
+	}
+	u.roots = append(u.roots, u.build(0, n))
+	u.sccs = append(u.sccs, n)
+	return u
+}
+
+// Version returns the current version. Version 0 is the initial state.
+func (u *Persistent) Version() int64 {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return int64(len(u.roots) - 1)
+}
+
+func (u *Persistent) Find(x int64) int64 {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return u.find(len(u.roots)-1, x)
+}
+
+// Merge merges the sets containing x and y and produces a new version, even if they were
+// already in the same set.
+func (u *Persistent) Merge(x, y int64) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.merge(x, y)
+}
+
+func (u *Persistent) SameSet(x, y int64) bool {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	v := len(u.roots) - 1
+	return u.find(v, x) == u.find(v, y)
+}
+
+func (u *Persistent) SetSize(x int64) int64 {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	v := len(u.roots) - 1
+	return u.get(u.roots[v], 0, u.n, u.find(v, x)).size
+}
+
+func (u *Persistent) SCC() int64 {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return u.sccs[len(u.sccs)-1]
+}
+
+// Squash is a no-op: path compression would break the sharing between versions.
+func (u *Persistent) Squash() {}
+
+// FindAt returns the representative of x as of the given version.
+// The version must be in [0, Version()].
+func (u *Persistent) FindAt(version, x int64) int64 {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return u.find(int(version), x)
+}
+
+// SameSetAt reports whether x and y were in the same set as of the given version.
+func (u *Persistent) SameSetAt(version, x, y int64) bool {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return u.find(int(version), x) == u.find(int(version), y)
+}
+
+// SetSizeAt returns the size of the set containing x as of the given version.
+func (u *Persistent) SetSizeAt(version, x int64) int64 {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return u.get(u.roots[version], 0, u.n, u.find(int(version), x)).size
+}
+
+// SCCAt returns the number of sets as of the given version.
+func (u *Persistent) SCCAt(version int64) int64 {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return u.sccs[version]
+}
+
+func (u *Persistent) build(l, r int64) int {
+	if l == r {
+		u.nodes = append(u.nodes, pnode{parent: l, size: 1})
+		return len(u.nodes) - 1
+	}
+	m := (l + r) >> 1
+	lc, rc := u.build(l, m), u.build(m+1, r)
+	u.nodes = append(u.nodes, pnode{lchild: lc, rchild: rc})
+	return len(u.nodes) - 1
+}
+
+func (u *Persistent) get(root int, l, r, x int64) pnode {
+	for l < r {
+		m := (l + r) >> 1
+		if x <= m {
+			root, r = u.nodes[root].lchild, m
+		} else {
+			root, l = u.nodes[root].rchild, m+1
+		}
+	}
+	return u.nodes[root]
+}
+
+// set returns the root of a new tree that shares all nodes with `root` except the path to x.
+func (u *Persistent) set(root int, l, r, x int64, leaf pnode) int {
+	if l == r {
+		u.nodes = append(u.nodes, leaf)
+		return len(u.nodes) - 1
+	}
+	n := u.nodes[root]
+	m := (l + r) >> 1
+	if x <= m {
+		n.lchild = u.set(n.lchild, l, m, x, leaf)
+	} else {
+		n.rchild = u.set(n.rchild, m+1, r, x, leaf)
+	}
+	u.nodes = append(u.nodes, n)
+	return len(u.nodes) - 1
+}
+
+func (u *Persistent) find(version int, x int64) int64 {
+	root := u.roots[version]
+	for {
+		p := u.get(root, 0, u.n, x).parent
+		if p == x {
+			return x
+		}
+		x = p
+	}
+}
+
+func (u *Persistent) merge(x, y int64) bool {
+	v := len(u.roots) - 1
+	root, scc := u.roots[v], u.sccs[v]
+	fx, fy := u.find(v, x), u.find(v, y)
+	if fx == fy {
+		u.roots, u.sccs = append(u.roots, root), append(u.sccs, scc)
+		return false
+	}
+	nx, ny := u.get(root, 0, u.n, fx), u.get(root, 0, u.n, fy)
+	if nx.rank > ny.rank {
+		fx, fy, nx, ny = fy, fx, ny, nx
+	}
+	nx.parent = fy
+	ny.size += nx.size
+	if nx.rank == ny.rank {
+		ny.rank++
+	}
+	root = u.set(root, 0, u.n, fx, nx)
+	root = u.set(root, 0, u.n, fy, ny)
+	u.roots, u.sccs = append(u.roots, root), append(u.sccs, scc-1)
+	return true
+}
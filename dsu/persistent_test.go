@@ -0,0 +1,43 @@
+package dsu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPersistent(t *testing.T) {
+	n := int64(10)
+	dsu := NewPersistent(n)
+	for i := int64(0); i <= n; i++ {
+		assert.Equal(t, i, dsu.Find(i))
+	}
+	assert.Equal(t, int64(0), dsu.Version())
+
+	assert.True(t, dsu.Merge(2, 3))
+	assert.True(t, dsu.Merge(4, 5))
+	assert.False(t, dsu.Merge(3, 2))
+	assert.True(t, dsu.Merge(2, 4))
+	assert.Equal(t, int64(4), dsu.Version())
+
+	// Current version.
+	assert.True(t, dsu.SameSet(2, 5))
+	assert.Equal(t, int64(4), dsu.SetSize(3))
+	assert.Equal(t, int64(7), dsu.SCC())
+
+	// Historical versions.
+	assert.False(t, dsu.SameSetAt(0, 2, 3))
+	assert.True(t, dsu.SameSetAt(1, 2, 3))
+	assert.False(t, dsu.SameSetAt(1, 4, 5))
+	assert.True(t, dsu.SameSetAt(2, 4, 5))
+	assert.False(t, dsu.SameSetAt(3, 2, 5))
+	assert.True(t, dsu.SameSetAt(4, 2, 5))
+	assert.Equal(t, dsu.FindAt(2, 2), dsu.FindAt(3, 3))
+	assert.Equal(t, int64(2), dsu.SetSizeAt(3, 5))
+	assert.Equal(t, int64(10), dsu.SCCAt(0))
+	assert.Equal(t, int64(8), dsu.SCCAt(3))
+
+	for i := int64(0); i <= n; i++ {
+		assert.Equal(t, i, dsu.FindAt(0, i))
+	}
+}
@@ -0,0 +1,150 @@
+package dynamic
+
+import (
+	"fmt"
+
+	"github.com/binacsgo/datastructure/splay"
+)
+
+// Sequence is a rope-like list built on dynamicSplay, where the position of an element is
+// given by the subtree sizes instead of Compare. Positional edits, Reverse, Concat and
+// SplitAt all cost O(log n) amortized.
+// Sequence is not safe for concurrent use.
+type Sequence[T any] struct {
+	s *dynamicSplay
+}
+
+func NewSequence[T any]() *Sequence[T] {
+	return &Sequence[T]{s: New().(*dynamicSplay)}
+}
+
+// seqObj is the StoredObj used by Sequence, which is never compared or indexed.
+type seqObj[T any] struct {
+	val T
+}
+
+func (o *seqObj[T]) Key() string                          { return "" }
+func (o *seqObj[T]) String() string                       { return fmt.Sprint(o.val) }
+func (o *seqObj[T]) MakeMaintainInfo() splay.MaintainInfo { return splay.NilObj.MakeMaintainInfo() }
+func (o *seqObj[T]) Compare(splay.Comparable) bool        { return false }
+
+func (q *Sequence[T]) Len() int {
+	return size(q.s.root)
+}
+
+// node returns the node at position i, where -1 means minv and Len() means maxv.
+func (q *Sequence[T]) node(i int) *node {
+	switch i {
+	case -1:
+		return q.s.minv
+	case q.Len():
+		return q.s.maxv
+	}
+	return q.s.kth(i)
+}
+
+// interval brings together the elements in [l, r) in a subtree and returns its root. The
+// caller guarantees that 0 <= l <= r <= Len().
+func (q *Sequence[T]) interval(l, r int) *node {
+	prev := q.node(l - 1)
+	q.s.splay(prev, nil)
+	next := q.node(r)
+	q.s.splay(next, prev)
+	return next.child[0]
+}
+
+// InsertAt inserts v before the element at position i, or appends it if i is Len().
+// Returns false if i is out of range.
+func (q *Sequence[T]) InsertAt(i int, v T) bool {
+	if i < 0 || i > q.Len() {
+		return false
+	}
+	q.interval(i, i)
+	next := q.s.root.child[1]
+	n := newNode(&seqObj[T]{val: v}, next)
+	next.child[0] = n
+	q.s.maintain(n)
+	q.s.maintain(next)
+	q.s.maintain(q.s.root)
+	return true
+}
+
+// DeleteAt removes and returns the element at position i.
+func (q *Sequence[T]) DeleteAt(i int) (T, bool) {
+	if i < 0 || i >= q.Len() {
+		var zero T
+		return zero, false
+	}
+	n := q.interval(i, i+1)
+	next := n.parent
+	next.child[0], n.parent = nil, nil
+	q.s.maintain(next)
+	q.s.maintain(q.s.root)
+	return n.obj.(*seqObj[T]).val, true
+}
+
+// At returns the element at position i.
+func (q *Sequence[T]) At(i int) (T, bool) {
+	if i < 0 || i >= q.Len() {
+		var zero T
+		return zero, false
+	}
+	n := q.s.kth(i)
+	q.s.splay(n, nil)
+	return n.obj.(*seqObj[T]).val, true
+}
+
+// Reverse reverses the elements in [l, r) by attaching a lazy flip tag. Returns false if the
+// range is invalid.
+func (q *Sequence[T]) Reverse(l, r int) bool {
+	if l < 0 || l > r || r > q.Len() {
+		return false
+	}
+	if n := q.interval(l, r); n != nil {
+		n.flip = !n.flip
+	}
+	return true
+}
+
+// Concat appends all elements of other to q, and other becomes empty.
+func (q *Sequence[T]) Concat(other *Sequence[T]) {
+	if other == q || other.Len() == 0 {
+		return
+	}
+	q.s.join(other.s)
+}
+
+// SplitAt moves the elements from position i on to a new Sequence and returns it. Returns
+// nil if i is out of range.
+func (q *Sequence[T]) SplitAt(i int) *Sequence[T] {
+	if i < 0 || i > q.Len() {
+		return nil
+	}
+	q.s.splay(q.s.minv, nil)
+	next := q.node(i)
+	q.s.splay(next, q.s.minv)
+	return &Sequence[T]{s: q.s.split(next)}
+}
+
+// Range traverses the elements in order, and stops if f returns false.
+func (q *Sequence[T]) Range(f func(int, T) bool) {
+	i := 0
+	q.s.traverse(q.s.root, false, func(n *node) bool {
+		i++
+		return f(i-1, n.obj.(*seqObj[T]).val)
+	})
+}
+
+// Slice returns all elements in order.
+func (q *Sequence[T]) Slice() []T {
+	ret := make([]T, 0, q.Len())
+	q.Range(func(_ int, v T) bool {
+		ret = append(ret, v)
+		return true
+	})
+	return ret
+}
+
+func (q *Sequence[T]) String() string {
+	return fmt.Sprint(q.Slice())
+}
@@ -1,6 +1,7 @@
 package dynamic
 
 import (
+	"sort"
 	"strings"
 
 	"github.com/binacsgo/datastructure/splay"
@@ -15,6 +16,11 @@ type node struct {
 	parent *node
 	obj    splay.StoredObj
 	info   splay.MaintainInfo
+	// size is the number of objects in the subtree, excluding the sentinels.
+	size int
+	// flip is the pending tag of Sequence.Reverse, which means the children of every node in
+	// the subtree, including the current one, should be swapped.
+	flip bool
 }
 
 func newNode(o splay.StoredObj, p *node) *node {
@@ -32,16 +38,12 @@ type dynamicSplay struct {
 	index            map[string]*node
 	chooseChildIndex func(splay.Comparable, *node) int
 	maintain         func(*node)
+	// path is reused by splay to avoid allocations.
+	path []*node
 }
 
 func New() splay.Splay {
-	s := &dynamicSplay{
-		minv:  newNode(splay.MinObj, nil),
-		maxv:  newNode(splay.MaxObj, nil),
-		index: make(map[string]*node),
-	}
-	s.minv.child[1], s.maxv.parent = s.maxv, s.minv
-	s.root = s.minv
+	s := &dynamicSplay{}
 	s.chooseChildIndex = func(o splay.Comparable, n *node) int {
 		if n == s.minv || n != s.maxv && o.Compare(n.obj) {
 			return 1
@@ -57,10 +59,35 @@ func New() splay.Splay {
 			rightChildInfo = n.child[1].info
 		}
 		n.info.Maintain(leftChildInfo, rightChildInfo)
+		n.size = s.weight(n) + size(n.child[0]) + size(n.child[1])
 	}
+	s.reset()
 	return s
 }
 
+// reset makes the splay empty.
+func (s *dynamicSplay) reset() {
+	s.minv, s.maxv = newNode(splay.MinObj, nil), newNode(splay.MaxObj, nil)
+	s.index = make(map[string]*node)
+	s.minv.child[1], s.maxv.parent = s.maxv, s.minv
+	s.root = s.minv
+}
+
+func size(n *node) int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+// weight returns 0 for the sentinels and 1 for the others.
+func (s *dynamicSplay) weight(n *node) int {
+	if n == s.minv || n == s.maxv {
+		return 0
+	}
+	return 1
+}
+
 func (s *dynamicSplay) Insert(v splay.StoredObj) bool {
 	if _, ok := s.index[v.Key()]; ok {
 		return false
@@ -68,6 +95,7 @@ func (s *dynamicSplay) Insert(v splay.StoredObj) bool {
 	n := s.root
 	var p *node
 	for n != nil {
+		s.pushDown(n)
 		p, n = n, n.child[s.chooseChildIndex(v, n)]
 	}
 	n = newNode(v, p)
@@ -100,90 +128,518 @@ func (s *dynamicSplay) Delete(v splay.StoredObj) bool {
 	return true
 }
 
+// InsertBatch inserts the objects one by one in ascending order if there are fewer of them
+// than the existing ones, and otherwise merges them with the existing ones and rebuilds the
+// splay in O(n + m).
+func (s *dynamicSplay) InsertBatch(objs []splay.StoredObj) int {
+	sorted := append([]splay.StoredObj(nil), objs...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[j].Compare(sorted[i]) })
+	count := 0
+	if len(sorted) < s.Len() {
+		for _, obj := range sorted {
+			if s.Insert(obj) {
+				count++
+			}
+		}
+		return count
+	}
+
+	merged := make([]splay.StoredObj, 0, s.Len()+len(sorted))
+	seen := make(map[string]bool, len(sorted))
+	add := func(obj splay.StoredObj) {
+		if _, ok := s.index[obj.Key()]; ok || seen[obj.Key()] {
+			return
+		}
+		seen[obj.Key()] = true
+		merged = append(merged, obj)
+		count++
+	}
+	i := 0
+	s.traverse(s.root, false, func(n *node) bool {
+		// New objects go before the existing equal ones, the same as Insert.
+		for ; i < len(sorted) && !sorted[i].Compare(n.obj); i++ {
+			add(sorted[i])
+		}
+		merged = append(merged, n.obj)
+		return true
+	})
+	for ; i < len(sorted); i++ {
+		add(sorted[i])
+	}
+	if !s.BuildFromSorted(merged) {
+		// The rebuild rejects the order if Compare is inconsistent, and changes nothing.
+		count = 0
+		for _, obj := range sorted {
+			if s.Insert(obj) {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+func (s *dynamicSplay) BuildFromSorted(objs []splay.StoredObj) bool {
+	index := make(map[string]*node, len(objs))
+	for i, obj := range objs {
+		if _, ok := index[obj.Key()]; ok || i > 0 && objs[i-1].Compare(obj) {
+			return false
+		}
+		index[obj.Key()] = nil
+	}
+	s.reset()
+	if len(objs) == 0 {
+		return true
+	}
+
+	nodes := make([]*node, len(objs))
+	for i, obj := range objs {
+		nodes[i] = newNode(obj, nil)
+		index[obj.Key()] = nodes[i]
+	}
+	var build func(l, r int, p *node) *node
+	build = func(l, r int, p *node) *node {
+		if l >= r {
+			return nil
+		}
+		mid := (l + r) / 2
+		n := nodes[mid]
+		n.parent = p
+		n.child[0], n.child[1] = build(l, mid, n), build(mid+1, r, n)
+		s.maintain(n)
+		return n
+	}
+	s.root = build(0, len(nodes), nil)
+	// The sentinels become the leftmost and the rightmost leaves, which changes nothing
+	// maintained by their ancestors.
+	first, last := nodes[0], nodes[len(nodes)-1]
+	s.minv.child[1] = nil
+	first.child[0], s.minv.parent = s.minv, first
+	last.child[1], s.maxv.parent = s.maxv, last
+	s.index = index
+	return true
+}
+
+func (s *dynamicSplay) Update(obj splay.StoredObj) bool {
+	n, ok := s.index[obj.Key()]
+	if !ok {
+		return false
+	}
+	s.splay(n, nil)
+	prev, next := n.child[0], n.child[1]
+	for ; prev.child[1] != nil; prev = prev.child[1] {
+	}
+	for ; next.child[0] != nil; next = next.child[0] {
+	}
+	if (prev == s.minv || !prev.obj.Compare(obj)) && (next == s.maxv || !obj.Compare(next.obj)) {
+		// The position does not change, and n is the root without pending tags.
+		n.obj, n.info = obj, obj.MakeMaintainInfo()
+		s.maintain(n)
+		return true
+	}
+	s.Delete(n.obj)
+	s.Insert(obj)
+	return true
+}
+
 func (s *dynamicSplay) Get(obj splay.StoredObj) splay.StoredObj {
 	n, ok := s.index[obj.Key()]
 	if !ok {
 		return nil
 	}
+	// Splaying pushes down the pending tags on the path, so that the object is up to date.
+	s.splay(n, nil)
 	return n.obj
 }
 
 func (s *dynamicSplay) Partition(obj splay.Comparable) splay.StoredObj {
-	s.splay(s.minv, nil)
+	if n := s.interval(nil, obj); n != nil {
+		return n.obj
+	}
+	return nil
+}
+
+func (s *dynamicSplay) QueryRange(lo, hi splay.Comparable) splay.MaintainInfo {
+	if !hi.Compare(lo) {
+		return nil
+	}
+	if n := s.interval(lo, hi); n != nil {
+		return n.info.Clone()
+	}
+	return nil
+}
+
+func (s *dynamicSplay) QueryPrefix(hi splay.Comparable) splay.MaintainInfo {
+	if n := s.interval(nil, hi); n != nil {
+		return n.info.Clone()
+	}
+	return nil
+}
+
+func (s *dynamicSplay) ApplyRange(lo, hi splay.Comparable, tag interface{}) bool {
+	if !hi.Compare(lo) {
+		return false
+	}
+	n := s.interval(lo, hi)
+	if n == nil {
+		return false
+	}
+	lazy, ok := n.info.(splay.LazyMaintainInfo)
+	if !ok {
+		return false
+	}
+	lazy.Apply(tag)
+	s.maintain(n.parent)
+	s.maintain(s.root)
+	return true
+}
+
+// interval brings together all objects in [lo, hi) in a subtree and returns its root, where
+// a nil lo means no lower bound. The caller guarantees that lo is smaller than hi.
+func (s *dynamicSplay) interval(lo, hi splay.Comparable) *node {
+	prev := s.minv
+	if lo != nil {
+		for p := s.root; p != nil; {
+			if s.chooseChildIndex(lo, p) == 1 {
+				prev = p
+				p = p.child[1]
+			} else {
+				p = p.child[0]
+			}
+		}
+	}
+	s.splay(prev, nil)
 	var next *node
-	for p := s.root; p != nil; {
-		if s.chooseChildIndex(obj, p) == 1 {
+	for p := prev.child[1]; p != nil; {
+		if s.chooseChildIndex(hi, p) == 1 {
 			p = p.child[1]
 		} else {
 			next = p
 			p = p.child[0]
 		}
 	}
-	s.splay(next, s.minv)
-	if next.child[0] == nil {
-		return nil
-	}
-	return next.child[0].obj
+	s.splay(next, prev)
+	return next.child[0]
 }
 
 func (s *dynamicSplay) Range(f splay.RangeFunc) {
-	var dfs func(n *node)
-	dfs = func(n *node) {
-		if n == nil {
+	s.traverse(s.root, false, func(n *node) bool {
+		f(n.obj)
+		return true
+	})
+}
+
+func (s *dynamicSplay) ConditionRange(f splay.ConditionRangeFunc) {
+	s.traverse(s.root, false, func(n *node) bool {
+		return f(n.obj)
+	})
+}
+
+func (s *dynamicSplay) ReverseRange(f splay.ConditionRangeFunc) {
+	s.traverse(s.root, true, func(n *node) bool {
+		return f(n.obj)
+	})
+}
+
+func (s *dynamicSplay) RangeBetween(lo, hi splay.Comparable, f splay.ConditionRangeFunc) {
+	if !hi.Compare(lo) {
+		return
+	}
+	s.traverse(s.interval(lo, hi), false, func(n *node) bool {
+		return f(n.obj)
+	})
+}
+
+// traverse visits the objects in the subtree in mid-order, or in reverse mid-order if
+// reverse is true, and stops once f returns false. The sentinels are skipped.
+func (s *dynamicSplay) traverse(root *node, reverse bool, f func(*node) bool) {
+	first := 0
+	if reverse {
+		first = 1
+	}
+	var stack []*node
+	for n := root; n != nil || len(stack) > 0; {
+		for ; n != nil; n = n.child[first] {
+			s.pushDown(n)
+			stack = append(stack, n)
+		}
+		n = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if n != s.minv && n != s.maxv && !f(n) {
 			return
 		}
-		dfs(n.child[0])
-		if n != s.minv && n != s.maxv {
-			f(n.obj)
+		n = n.child[first^1]
+	}
+}
+
+// Split costs O(log n) amortized for the tree, plus O(min(|left|, |right|)) to split the index.
+func (s *dynamicSplay) Split(obj splay.Comparable) (splay.Splay, splay.Splay) {
+	s.splay(s.minv, nil)
+	var next *node
+	for p := s.root; p != nil; {
+		if s.chooseChildIndex(obj, p) == 1 {
+			p = p.child[1]
+		} else {
+			next = p
+			p = p.child[0]
 		}
-		dfs(n.child[1])
 	}
-	dfs(s.root)
+	s.splay(next, s.minv)
+	right := s.split(next)
+	l := s.maxv.child[0]
+
+	// Move the smaller part of the index.
+	if size(l) <= size(next) {
+		index := make(map[string]*node, size(l))
+		walk(l, func(n *node) {
+			index[n.obj.Key()] = n
+			delete(s.index, n.obj.Key())
+		})
+		s.index, right.index = index, s.index
+	} else {
+		walk(next, func(n *node) {
+			if n != right.maxv {
+				right.index[n.obj.Key()] = n
+				delete(s.index, n.obj.Key())
+			}
+		})
+	}
+	return s, right
 }
 
-func (s *dynamicSplay) ConditionRange(f splay.ConditionRangeFunc) {
-	var dfs func(n *node)
-	dfs = func(n *node) {
-		if n == nil {
-			return
+// Join costs O(log n) amortized for the tree, plus O(min(n, m)) to merge the index.
+func (s *dynamicSplay) Join(other splay.Splay) bool {
+	o, ok := other.(*dynamicSplay)
+	if !ok || o == s {
+		return false
+	}
+	if o.Len() == 0 {
+		return true
+	}
+	if s.Len() > 0 {
+		if s.Kth(s.Len() - 1).Compare(o.Kth(0)) {
+			return false
 		}
-		dfs(n.child[0])
-		if n != s.minv && n != s.maxv {
-			if !f(n.obj) {
-				return
+		small, large := s.index, o.index
+		if len(small) > len(large) {
+			small, large = large, small
+		}
+		for k := range small {
+			if _, ok := large[k]; ok {
+				return false
 			}
 		}
-		dfs(n.child[1])
 	}
-	dfs(s.root)
+
+	s.join(o)
+	return true
+}
+
+// split moves next and the objects after it to a new splay, where next is the right child
+// of minv at the root.
+func (s *dynamicSplay) split(next *node) *dynamicSplay {
+	// The left child of next holds all the objects before it.
+	l := next.child[0]
+	next.child[0] = nil
+	s.maintain(next)
+
+	right := New().(*dynamicSplay)
+	// The new maxv of right becomes the maxv of the left part, and right takes the old one.
+	maxv := right.maxv
+	right.minv.child[1], next.parent = next, right.minv
+	right.maxv = s.maxv
+	right.maintain(right.minv)
+
+	s.maxv = maxv
+	s.minv.child[1], maxv.parent = maxv, s.minv
+	maxv.child[0] = l
+	if l != nil {
+		l.parent = maxv
+	}
+	s.maintain(maxv)
+	s.maintain(s.minv)
+	return right
+}
+
+// join appends all objects of the non-empty o to s without any check, and makes o empty.
+func (s *dynamicSplay) join(o *dynamicSplay) {
+	// Bring the max object of s to the root, whose right child is maxv only.
+	s.splay(s.maxv, nil)
+	l := s.maxv.child[0]
+	// Bring the min object of o to the root, whose left child is minv only.
+	r := o.kth(0)
+	o.splay(r, nil)
+	r.child[0], l.parent = l, r
+	s.root, s.maxv = r, o.maxv
+	s.maintain(r)
+
+	small, large := s.index, o.index
+	if len(small) > len(large) {
+		small, large = large, small
+	}
+	for k, n := range small {
+		large[k] = n
+	}
+	s.index = large
+	o.reset()
 }
 
 func (s *dynamicSplay) Len() int {
 	return len(s.index)
 }
 
-func (s *dynamicSplay) String() string {
-	output := &strings.Builder{}
-	var dfs func(*node)
-	dfs = func(n *node) {
-		if n == nil {
-			return
+func (s *dynamicSplay) Kth(k int) splay.StoredObj {
+	if k < 0 || k >= s.Len() {
+		return nil
+	}
+	n := s.kth(k)
+	s.splay(n, nil)
+	return n.obj
+}
+
+// kth returns the node of the k-th smallest object without splaying it. The caller
+// guarantees that k is in range.
+func (s *dynamicSplay) kth(k int) *node {
+	n := s.root
+	for {
+		s.pushDown(n)
+		ls, w := size(n.child[0]), s.weight(n)
+		if k < ls {
+			n = n.child[0]
+		} else if k < ls+w {
+			return n
+		} else {
+			k -= ls + w
+			n = n.child[1]
 		}
-		dfs(n.child[0])
-		if n != s.minv && n != s.maxv {
-			output.WriteString(n.obj.Key() + ",")
+	}
+}
+
+func (s *dynamicSplay) Rank(obj splay.StoredObj) int {
+	n, ok := s.index[obj.Key()]
+	if !ok {
+		return -1
+	}
+	s.splay(n, nil)
+	return size(n.child[0])
+}
+
+func (s *dynamicSplay) CountLess(obj splay.Comparable) int {
+	ret := 0
+	var last *node
+	for n := s.root; n != nil; {
+		last = n
+		if s.chooseChildIndex(obj, n) == 1 {
+			ret += size(n.child[0]) + s.weight(n)
+			n = n.child[1]
+		} else {
+			n = n.child[0]
 		}
-		dfs(n.child[1])
 	}
-	dfs(s.root)
+	s.splay(last, nil)
+	return ret
+}
+
+func (s *dynamicSplay) CountInRange(lo, hi splay.Comparable) int {
+	if ret := s.CountLess(hi) - s.CountLess(lo); ret > 0 {
+		return ret
+	}
+	return 0
+}
+
+func (s *dynamicSplay) Min() splay.StoredObj {
+	return s.Kth(0)
+}
+
+func (s *dynamicSplay) Max() splay.StoredObj {
+	return s.Kth(s.Len() - 1)
+}
+
+func (s *dynamicSplay) LowerBound(obj splay.Comparable) splay.StoredObj {
+	_, next := s.bound(obj, false)
+	return s.result(next)
+}
+
+func (s *dynamicSplay) UpperBound(obj splay.Comparable) splay.StoredObj {
+	_, next := s.bound(obj, true)
+	return s.result(next)
+}
+
+func (s *dynamicSplay) Floor(obj splay.Comparable) splay.StoredObj {
+	prev, _ := s.bound(obj, true)
+	return s.result(prev)
+}
+
+func (s *dynamicSplay) Ceiling(obj splay.Comparable) splay.StoredObj {
+	return s.LowerBound(obj)
+}
+
+func (s *dynamicSplay) Prev(obj splay.StoredObj) splay.StoredObj {
+	n, ok := s.index[obj.Key()]
+	if !ok {
+		return nil
+	}
+	s.splay(n, nil)
+	p := n.child[0]
+	for ; p.child[1] != nil; p = p.child[1] {
+	}
+	return s.result(p)
+}
+
+func (s *dynamicSplay) Next(obj splay.StoredObj) splay.StoredObj {
+	n, ok := s.index[obj.Key()]
+	if !ok {
+		return nil
+	}
+	s.splay(n, nil)
+	p := n.child[1]
+	for ; p.child[0] != nil; p = p.child[0] {
+	}
+	return s.result(p)
+}
+
+// bound returns the last node before obj and the first node after obj, where the nodes
+// equal to obj are after it unless strict is true.
+func (s *dynamicSplay) bound(obj splay.Comparable, strict bool) (prev, next *node) {
+	for p := s.root; p != nil; {
+		before := s.chooseChildIndex(obj, p) == 1
+		if strict && p != s.maxv {
+			before = p == s.minv || !p.obj.Compare(obj)
+		}
+		if before {
+			prev, p = p, p.child[1]
+		} else {
+			next, p = p, p.child[0]
+		}
+	}
+	return
+}
+
+// result splays the node found by a lookup and returns its object, or nil for the sentinels.
+func (s *dynamicSplay) result(n *node) splay.StoredObj {
+	s.splay(n, nil)
+	if n == s.minv || n == s.maxv {
+		return nil
+	}
+	return n.obj
+}
+
+func (s *dynamicSplay) String() string {
+	output := &strings.Builder{}
+	s.traverse(s.root, false, func(n *node) bool {
+		output.WriteString(n.obj.Key() + ",")
+		return true
+	})
 	return output.String()
 }
 
 func (s *dynamicSplay) Clone() splay.Splay {
 	clone := New().(*dynamicSplay)
 	index := make(map[string]*node, len(s.index))
-	var dfs func(*node, *node) *node
-	dfs = func(n, p *node) *node {
+	// clones holds the pairs of the original nodes and their clones whose children are not
+	// cloned yet.
+	type pair struct{ n, nn *node }
+	var clones []pair
+	cloneNode := func(n, p *node) *node {
 		if n == nil {
 			return nil
 		}
@@ -199,10 +655,16 @@ func (s *dynamicSplay) Clone() splay.Splay {
 			nn.info = n.info.Clone() // ATTENTION
 			index[nn.obj.Key()] = nn
 		}
-		nn.child[0], nn.child[1] = dfs(n.child[0], nn), dfs(n.child[1], nn)
+		nn.size, nn.flip = n.size, n.flip
+		clones = append(clones, pair{n, nn})
 		return nn
 	}
-	clone.root = dfs(s.root, nil)
+	clone.root = cloneNode(s.root, nil)
+	for len(clones) > 0 {
+		c := clones[len(clones)-1]
+		clones = clones[:len(clones)-1]
+		c.nn.child[0], c.nn.child[1] = cloneNode(c.n.child[0], c.nn), cloneNode(c.n.child[1], c.nn)
+	}
 
 	clone.index = index
 	return clone
@@ -210,38 +672,66 @@ func (s *dynamicSplay) Clone() splay.Splay {
 
 func (s *dynamicSplay) PrintTree() string {
 	output := &strings.Builder{}
-	var dfs func(*node, *strings.Builder, bool)
-	dfs = func(n *node, prefixBuilder *strings.Builder, isBottom bool) {
-		prefix := prefixBuilder.String()
+	output.WriteString("SplayRoot\n")
+	// Visit the right subtree, the node and then the left subtree, so that the tree diagram
+	// is printed from top to bottom.
+	type frame struct {
+		n        *node
+		prefix   string
+		isBottom bool
+		visited  bool
+	}
+	stack := []frame{{n: s.root, isBottom: true}}
+	for len(stack) > 0 {
+		f := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if f.visited {
+			output.WriteString(f.prefix)
+			if f.isBottom {
+				output.WriteString("└── ")
+			} else {
+				output.WriteString("┌── ")
+			}
+			output.WriteString(f.n.obj.String() + "[" + f.n.info.String() + "]")
+			output.WriteByte('\n')
+			continue
+		}
 		handleChild := func(n *node, flag bool) {
 			if n == nil {
 				return
 			}
-			nextPrefixBuilder := &strings.Builder{}
-			nextPrefixBuilder.WriteString(prefix)
-			if isBottom != flag {
-				nextPrefixBuilder.WriteString("│   ")
+			if f.isBottom != flag {
+				stack = append(stack, frame{n: n, prefix: f.prefix + "│   ", isBottom: flag})
 			} else {
-				nextPrefixBuilder.WriteString("    ")
+				stack = append(stack, frame{n: n, prefix: f.prefix + "    ", isBottom: flag})
 			}
-			dfs(n, nextPrefixBuilder, flag)
-		}
-		handleChild(n.child[1], false)
-		output.WriteString(prefix)
-		if isBottom {
-			output.WriteString("└── ")
-		} else {
-			output.WriteString("┌── ")
 		}
-		output.WriteString(n.obj.String() + "[" + n.info.String() + "]")
-		output.WriteByte('\n')
-		handleChild(n.child[0], true)
+		handleChild(f.n.child[0], true)
+		f.visited = true
+		stack = append(stack, f)
+		handleChild(f.n.child[1], false)
 	}
-	output.WriteString("SplayRoot\n")
-	dfs(s.root, &strings.Builder{}, true)
 	return output.String()
 }
 
+// walk visits all nodes of the subtree rooted at n without recursion.
+func walk(n *node, f func(*node)) {
+	if n == nil {
+		return
+	}
+	stack := []*node{n}
+	for len(stack) > 0 {
+		n = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		f(n)
+		for _, c := range n.child {
+			if c != nil {
+				stack = append(stack, c)
+			}
+		}
+	}
+}
+
 // getChildIndex indicates whether `x` is the right child of `y`.
 func getChildIndex(x, y *node) int {
 	if y != nil && y.child[1] == x {
@@ -268,7 +758,40 @@ func (s *dynamicSplay) rotate(x *node) {
 	s.maintain(x)
 }
 
+// pushDown passes the pending tags of the node down to its children.
+func (s *dynamicSplay) pushDown(n *node) {
+	if n.flip {
+		n.child[0], n.child[1] = n.child[1], n.child[0]
+		for _, c := range n.child {
+			if c != nil {
+				c.flip = !c.flip
+			}
+		}
+		n.flip = false
+	}
+	lazy, ok := n.info.(splay.LazyMaintainInfo)
+	if !ok {
+		return
+	}
+	var leftChildInfo, rightChildInfo splay.MaintainInfo
+	if n.child[0] != nil && n.child[0] != s.minv {
+		leftChildInfo = n.child[0].info
+	}
+	if n.child[1] != nil && n.child[1] != s.maxv {
+		rightChildInfo = n.child[1].info
+	}
+	lazy.PushDown(leftChildInfo, rightChildInfo)
+}
+
 func (s *dynamicSplay) splay(x, k *node) {
+	// Clear the pending tags from the root to x before rotating.
+	s.path = s.path[:0]
+	for p := x; p != nil; p = p.parent {
+		s.path = append(s.path, p)
+	}
+	for i := len(s.path) - 1; i >= 0; i-- {
+		s.pushDown(s.path[i])
+	}
 	for x.parent != k {
 		y := x.parent
 		z := y.parent
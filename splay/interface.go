@@ -19,6 +19,20 @@ type MaintainInfo interface {
 	String() string
 }
 
+// LazyMaintainInfo is an optional interface of MaintainInfo for range updates with lazy tags.
+// A tag must never change the relative order of the objects.
+type LazyMaintainInfo interface {
+	MaintainInfo
+
+	// Apply applies the tag to the whole subtree rooted at the current node. The properties
+	// of the current node must be updated at once, while the tag is kept pending for the
+	// children until PushDown.
+	Apply(tag interface{})
+	// PushDown passes the pending tags down to the left and right children, either of which
+	// may be nil, and clears them. It is called before the children are accessed.
+	PushDown(MaintainInfo, MaintainInfo)
+}
+
 // StoredObj defines all the methods that need to be implemented by the element being stored.
 type StoredObj interface {
 	// Key returns the unique key used by the object in the splay.
@@ -40,8 +54,19 @@ type (
 
 // Splay defines all methods of the splay-tree.
 type Splay interface {
-	// Insert a StoredObj into the splay. Returns true if successful.
+	// Insert a StoredObj into the splay. Returns true if successful, or false without any
+	// change if the key already exists.
 	Insert(StoredObj) bool
+	// InsertBatch sorts the StoredObjs and inserts them. The ones whose keys already exist
+	// are skipped like Insert. Returns the number of inserted objects.
+	InsertBatch([]StoredObj) int
+	// BuildFromSorted replaces all objects of the splay with the StoredObjs in O(n), which
+	// must be in ascending order and have distinct keys. Returns false and changes nothing if
+	// the requirements are not met.
+	BuildFromSorted([]StoredObj) bool
+	// Update replaces the object of the same key with the StoredObj, and relocates it if its
+	// position in the order changes. Returns false if the key does not exist.
+	Update(StoredObj) bool
 	// Delete a StoredObj from the splay. Returns true if successful.
 	Delete(StoredObj) bool
 	// Get a StoredObj from the splay.
@@ -49,13 +74,72 @@ type Splay interface {
 	// Partition will bring together all objects strictly smaller than the current object
 	// in a subtree and return the root of the subtree.
 	Partition(Comparable) StoredObj
+	// QueryRange returns a clone of the MaintainInfo of the subtree that holds exactly the
+	// objects in [lo, hi). Returns nil if there is no such object.
+	QueryRange(lo, hi Comparable) MaintainInfo
+	// QueryPrefix returns a clone of the MaintainInfo of the subtree that holds exactly the
+	// objects strictly smaller than hi. Returns nil if there is no such object.
+	QueryPrefix(hi Comparable) MaintainInfo
+	// ApplyRange applies the tag to all objects in [lo, hi). Returns false if there is no such
+	// object or their MaintainInfo does not implement LazyMaintainInfo.
+	ApplyRange(lo, hi Comparable, tag interface{}) bool
 	// Range traverses the entire splay in mid-order.
 	Range(RangeFunc)
 	// ConditionRange traverses the entire splay in mid-order and ends the access immediately
 	// if ConditionRangeFunc returns false.
 	ConditionRange(ConditionRangeFunc)
+	// ReverseRange traverses the entire splay in reverse mid-order and ends the access
+	// immediately if ConditionRangeFunc returns false.
+	ReverseRange(ConditionRangeFunc)
+	// RangeBetween traverses the objects in [lo, hi) in mid-order and ends the access
+	// immediately if ConditionRangeFunc returns false.
+	RangeBetween(lo, hi Comparable, f ConditionRangeFunc)
+	// Split moves the objects strictly smaller than the Comparable into the left splay and
+	// the others into the right one. The splay itself is reused as either of the returned
+	// splays: the dynamic one always returns (s, other), while the static one returns
+	// (other, s) if the left part is not larger and (s, other) otherwise. So only the
+	// returned ones should be used afterwards:
+	//
+	//	left, right := s.Split(c)
+	//	// s is now the same splay as left or right, use left and right only.
+	Split(Comparable) (left, right Splay)
+	// Join moves all objects of the given splay, which must be of the same implementation and
+	// must not be smaller than any object in the current splay, to the current splay. Returns
+	// false and changes nothing if the requirements are not met. The given splay becomes empty.
+	// The package level Join offers the same operation as Join(left, right).
+	Join(Splay) bool
 	// Len returns the number of all objects in the splay.
 	Len() int
+	// Kth returns the k-th smallest object, where k starts from 0. Returns nil if k is out of range.
+	Kth(int) StoredObj
+	// Rank returns the number of objects strictly smaller than the StoredObj, or -1 if the
+	// StoredObj is not in the splay.
+	Rank(StoredObj) int
+	// CountLess returns the number of objects strictly smaller than the Comparable.
+	CountLess(Comparable) int
+	// CountInRange returns the number of objects in [lo, hi).
+	CountInRange(lo, hi Comparable) int
+	// Min returns the smallest object, or nil if the splay is empty.
+	Min() StoredObj
+	// Max returns the greatest object, or nil if the splay is empty.
+	Max() StoredObj
+	// LowerBound returns the smallest object not smaller than the Comparable, or nil if there
+	// is no such object.
+	LowerBound(Comparable) StoredObj
+	// UpperBound returns the smallest object strictly greater than the Comparable, or nil if
+	// there is no such object.
+	UpperBound(Comparable) StoredObj
+	// Floor returns the greatest object not greater than the Comparable, or nil if there is no
+	// such object.
+	Floor(Comparable) StoredObj
+	// Ceiling is the same as LowerBound.
+	Ceiling(Comparable) StoredObj
+	// Prev returns the object right before the StoredObj, or nil if the StoredObj is the
+	// smallest one or not in the splay.
+	Prev(StoredObj) StoredObj
+	// Next returns the object right after the StoredObj, or nil if the StoredObj is the
+	// greatest one or not in the splay.
+	Next(StoredObj) StoredObj
 	// String implements the String interface.
 	String() string
 	// Clone return a clone of the Splay.
@@ -64,6 +148,12 @@ type Splay interface {
 	PrintTree() string
 }
 
+// Join moves all objects of right to left, as left.Join(right) does, so that a splay split
+// by Split can be joined back with Join(left, right).
+func Join(left, right Splay) bool {
+	return left.Join(right)
+}
+
 // maintainInfoForLookup defines one of the simplest MaintainInfo implementations for lookups only.
 type maintainInfoForLookup struct{}
 
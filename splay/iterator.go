@@ -0,0 +1,62 @@
+package splay
+
+// Iterator is a cursor over the objects of a Splay. It moves by Splay.Next and Splay.Prev,
+// so a full traversal costs O(n) amortized without any recursion. The Iterator becomes
+// invalid once its current object is deleted from the Splay.
+type Iterator struct {
+	s   Splay
+	cur StoredObj
+}
+
+// NewIterator returns an Iterator of the Splay, which is not positioned at any object until
+// First, Last or Seek is called.
+func NewIterator(s Splay) *Iterator {
+	return &Iterator{s: s}
+}
+
+// First moves to the smallest object. Returns false if the Splay is empty.
+func (it *Iterator) First() bool {
+	it.cur = it.s.Min()
+	return it.cur != nil
+}
+
+// Last moves to the greatest object. Returns false if the Splay is empty.
+func (it *Iterator) Last() bool {
+	it.cur = it.s.Max()
+	return it.cur != nil
+}
+
+// Seek moves to the smallest object not smaller than the Comparable. Returns false if there
+// is no such object.
+func (it *Iterator) Seek(c Comparable) bool {
+	it.cur = it.s.LowerBound(c)
+	return it.cur != nil
+}
+
+// Next moves to the next object. Returns false if there is no such object, and the Iterator
+// is no longer positioned.
+func (it *Iterator) Next() bool {
+	if it.cur != nil {
+		it.cur = it.s.Next(it.cur)
+	}
+	return it.cur != nil
+}
+
+// Prev moves to the previous object. Returns false if there is no such object, and the
+// Iterator is no longer positioned.
+func (it *Iterator) Prev() bool {
+	if it.cur != nil {
+		it.cur = it.s.Prev(it.cur)
+	}
+	return it.cur != nil
+}
+
+// Valid returns true if the Iterator is positioned at an object.
+func (it *Iterator) Valid() bool {
+	return it.cur != nil
+}
+
+// Obj returns the current object, or nil if the Iterator is not positioned.
+func (it *Iterator) Obj() StoredObj {
+	return it.cur
+}
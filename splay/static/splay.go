@@ -1,6 +1,7 @@
 package static
 
 import (
+	"sort"
 	"strconv"
 	"strings"
 
@@ -12,6 +13,8 @@ type node struct {
 	parent         int
 	key            string
 	obj            splay.StoredObj
+	// size is the number of objects in the subtree, excluding the sentinels.
+	size int
 }
 
 func newNode(o splay.StoredObj, p int) node {
@@ -32,19 +35,15 @@ type staticSplay struct {
 
 	chooseChildIndex func(splay.Comparable, int) int
 	maintain         func(int)
+	// path is reused by splay to avoid allocations.
+	path []int
 }
 
 func New() splay.Splay {
 	s := &staticSplay{
-		minv:  1,
-		maxv:  2,
-		hash:  make(map[string]int),
-		items: []node{newNode(splay.NilObj, -1), newNode(splay.MinObj, 0), newNode(splay.MaxObj, 1)},
-		infos: []splay.MaintainInfo{splay.NilObj.MakeMaintainInfo(), splay.MinObj.MakeMaintainInfo(), splay.MaxObj.MakeMaintainInfo()},
-		count: 2,
+		minv: 1,
+		maxv: 2,
 	}
-	s.items[s.minv].rchild, s.items[s.maxv].parent = s.maxv, s.minv
-	s.root = s.minv
 	s.chooseChildIndex = func(o splay.Comparable, n int) int {
 		if n == s.minv || n != s.maxv && o.Compare(s.items[n].obj) {
 			return 1
@@ -61,17 +60,37 @@ func New() splay.Splay {
 			rightChildInfo = s.infos[n.rchild]
 		}
 		s.infos[i].Maintain(leftChildInfo, rightChildInfo)
+		n.size = s.weight(i) + s.items[n.lchild].size + s.items[n.rchild].size
 	}
+	s.reset()
 	return s
 }
 
+// reset makes the splay empty.
+func (s *staticSplay) reset() {
+	s.hash = make(map[string]int)
+	s.items = []node{newNode(splay.NilObj, -1), newNode(splay.MinObj, 0), newNode(splay.MaxObj, 1)}
+	s.infos = []splay.MaintainInfo{splay.NilObj.MakeMaintainInfo(), splay.MinObj.MakeMaintainInfo(), splay.MaxObj.MakeMaintainInfo()}
+	s.count = 2
+	s.items[s.minv].rchild, s.items[s.maxv].parent = s.maxv, s.minv
+	s.root = s.minv
+}
+
+// weight returns 0 for the sentinels and 1 for the others.
+func (s *staticSplay) weight(i int) int {
+	if i == 0 || i == s.minv || i == s.maxv {
+		return 0
+	}
+	return 1
+}
+
 func (s *staticSplay) Insert(v splay.StoredObj) bool {
-	if i, ok := s.hash[v.Key()]; ok {
-		s.items[i].obj = v
+	if _, ok := s.hash[v.Key()]; ok {
 		return false
 	}
 	p, n := 0, s.root
 	for n != 0 {
+		s.pushDown(n)
 		p = n
 		if s.chooseChildIndex(v, n) == 1 {
 			n = s.items[n].rchild
@@ -122,33 +141,125 @@ func (s *staticSplay) Delete(v splay.StoredObj) bool {
 	s.maintain(pre)
 	delete(s.hash, v.Key())
 
-	lastIndex := s.count
-	if i != lastIndex {
-		lastNode := &s.items[lastIndex]
-		lastParent, lastLeftChild, lastRightChild := lastNode.parent, lastNode.lchild, lastNode.rchild
-		if lastParent != 0 {
-			if s.getChildIndex(lastIndex, lastParent) == 1 {
-				s.items[lastParent].rchild = i
-			} else {
-				s.items[lastParent].lchild = i
+	if i != s.count {
+		s.relocate(s.count, i)
+	}
+	s.items = s.items[:s.count]
+	s.infos = s.infos[:s.count]
+	s.count--
+	return true
+}
+
+// InsertBatch inserts the objects one by one in ascending order if there are fewer of them
+// than the existing ones, and otherwise merges them with the existing ones and rebuilds the
+// splay in O(n + m).
+func (s *staticSplay) InsertBatch(objs []splay.StoredObj) int {
+	sorted := append([]splay.StoredObj(nil), objs...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[j].Compare(sorted[i]) })
+	count := 0
+	if len(sorted) < s.Len() {
+		for _, obj := range sorted {
+			if s.Insert(obj) {
+				count++
 			}
 		}
-		if lastLeftChild != 0 {
-			s.items[lastLeftChild].parent = i
+		return count
+	}
+
+	merged := make([]splay.StoredObj, 0, s.Len()+len(sorted))
+	seen := make(map[string]bool, len(sorted))
+	add := func(obj splay.StoredObj) {
+		if _, ok := s.hash[obj.Key()]; ok || seen[obj.Key()] {
+			return
 		}
-		if lastRightChild != 0 {
-			s.items[lastRightChild].parent = i
+		seen[obj.Key()] = true
+		merged = append(merged, obj)
+		count++
+	}
+	i := 0
+	s.traverse(s.root, false, func(j int) bool {
+		// New objects go before the existing equal ones, the same as Insert.
+		for ; i < len(sorted) && !sorted[i].Compare(s.items[j].obj); i++ {
+			add(sorted[i])
 		}
-		s.hash[lastNode.key] = i
-		s.items[i] = s.items[lastIndex]
-		s.infos[i] = s.infos[lastIndex]
-		if s.root == lastIndex {
-			s.root = i
+		merged = append(merged, s.items[j].obj)
+		return true
+	})
+	for ; i < len(sorted); i++ {
+		add(sorted[i])
+	}
+	if !s.BuildFromSorted(merged) {
+		// The rebuild rejects the order if Compare is inconsistent, and changes nothing.
+		count = 0
+		for _, obj := range sorted {
+			if s.Insert(obj) {
+				count++
+			}
 		}
 	}
-	s.items = s.items[:s.count]
-	s.infos = s.infos[:s.count]
-	s.count--
+	return count
+}
+
+func (s *staticSplay) BuildFromSorted(objs []splay.StoredObj) bool {
+	hash := make(map[string]int, len(objs))
+	for i, obj := range objs {
+		if _, ok := hash[obj.Key()]; ok || i > 0 && objs[i-1].Compare(obj) {
+			return false
+		}
+		// The objects take the indexes right after the sentinels.
+		hash[obj.Key()] = i + 3
+	}
+	s.reset()
+	if len(objs) == 0 {
+		return true
+	}
+
+	s.items = append(s.items, make([]node, len(objs))...)
+	s.infos = append(s.infos, make([]splay.MaintainInfo, len(objs))...)
+	for i, obj := range objs {
+		s.items[i+3], s.infos[i+3] = newNode(obj, 0), obj.MakeMaintainInfo()
+	}
+	var build func(l, r, p int) int
+	build = func(l, r, p int) int {
+		if l >= r {
+			return 0
+		}
+		mid := (l + r) / 2
+		s.items[mid].parent = p
+		s.items[mid].lchild, s.items[mid].rchild = build(l, mid, mid), build(mid+1, r, mid)
+		s.maintain(mid)
+		return mid
+	}
+	first, last := 3, len(objs)+2
+	s.root = build(first, last+1, 0)
+	// The sentinels become the leftmost and the rightmost leaves, which changes nothing
+	// maintained by their ancestors.
+	s.items[s.minv].rchild = 0
+	s.items[first].lchild, s.items[s.minv].parent = s.minv, first
+	s.items[last].rchild, s.items[s.maxv].parent = s.maxv, last
+	s.hash, s.count = hash, last
+	return true
+}
+
+func (s *staticSplay) Update(obj splay.StoredObj) bool {
+	i, ok := s.hash[obj.Key()]
+	if !ok {
+		return false
+	}
+	s.splay(i, 0)
+	prev, next := s.items[i].lchild, s.items[i].rchild
+	for ; s.items[prev].rchild != 0; prev = s.items[prev].rchild {
+	}
+	for ; s.items[next].lchild != 0; next = s.items[next].lchild {
+	}
+	if (prev == s.minv || !s.items[prev].obj.Compare(obj)) && (next == s.maxv || !obj.Compare(s.items[next].obj)) {
+		// The position does not change, and i is the root without pending tags.
+		s.items[i].obj, s.infos[i] = obj, obj.MakeMaintainInfo()
+		s.maintain(i)
+		return true
+	}
+	s.Delete(s.items[i].obj)
+	s.Insert(obj)
 	return true
 }
 
@@ -157,78 +268,458 @@ func (s *staticSplay) Get(obj splay.StoredObj) splay.StoredObj {
 	if !ok {
 		return nil
 	}
+	// Splaying pushes down the pending tags on the path, so that the object is up to date.
+	s.splay(i, 0)
 	return s.items[i].obj
 }
 
 func (s *staticSplay) Partition(obj splay.Comparable) splay.StoredObj {
-	s.splay(s.minv, 0)
+	if p := s.interval(nil, obj); p != 0 {
+		return s.items[p].obj
+	}
+	return nil
+}
+
+func (s *staticSplay) QueryRange(lo, hi splay.Comparable) splay.MaintainInfo {
+	if !hi.Compare(lo) {
+		return nil
+	}
+	if p := s.interval(lo, hi); p != 0 {
+		return s.infos[p].Clone()
+	}
+	return nil
+}
+
+func (s *staticSplay) QueryPrefix(hi splay.Comparable) splay.MaintainInfo {
+	if p := s.interval(nil, hi); p != 0 {
+		return s.infos[p].Clone()
+	}
+	return nil
+}
+
+func (s *staticSplay) ApplyRange(lo, hi splay.Comparable, tag interface{}) bool {
+	if !hi.Compare(lo) {
+		return false
+	}
+	p := s.interval(lo, hi)
+	if p == 0 {
+		return false
+	}
+	lazy, ok := s.infos[p].(splay.LazyMaintainInfo)
+	if !ok {
+		return false
+	}
+	lazy.Apply(tag)
+	s.maintain(s.items[p].parent)
+	s.maintain(s.root)
+	return true
+}
+
+// interval brings together all objects in [lo, hi) in a subtree and returns its root, where
+// a nil lo means no lower bound. The caller guarantees that lo is smaller than hi.
+func (s *staticSplay) interval(lo, hi splay.Comparable) int {
+	prev := s.minv
+	if lo != nil {
+		for p := s.root; p != 0; {
+			if s.chooseChildIndex(lo, p) == 1 {
+				prev = p
+				p = s.items[p].rchild
+			} else {
+				p = s.items[p].lchild
+			}
+		}
+	}
+	s.splay(prev, 0)
 	var next int
-	for p := s.root; p != 0; {
-		if s.chooseChildIndex(obj, p) == 1 {
+	for p := s.items[prev].rchild; p != 0; {
+		if s.chooseChildIndex(hi, p) == 1 {
 			p = s.items[p].rchild
 		} else {
 			next = p
 			p = s.items[p].lchild
 		}
 	}
-	s.splay(next, s.minv)
-	p := s.items[next].lchild
-	if p == 0 {
-		return nil
-	}
-	return s.items[p].obj
+	s.splay(next, prev)
+	return s.items[next].lchild
 }
 
 func (s *staticSplay) Range(f splay.RangeFunc) {
-	var dfs func(int)
-	dfs = func(i int) {
-		if i == 0 {
+	s.traverse(s.root, false, func(i int) bool {
+		f(s.items[i].obj)
+		return true
+	})
+}
+
+func (s *staticSplay) ConditionRange(f splay.ConditionRangeFunc) {
+	s.traverse(s.root, false, func(i int) bool {
+		return f(s.items[i].obj)
+	})
+}
+
+func (s *staticSplay) ReverseRange(f splay.ConditionRangeFunc) {
+	s.traverse(s.root, true, func(i int) bool {
+		return f(s.items[i].obj)
+	})
+}
+
+func (s *staticSplay) RangeBetween(lo, hi splay.Comparable, f splay.ConditionRangeFunc) {
+	if !hi.Compare(lo) {
+		return
+	}
+	s.traverse(s.interval(lo, hi), false, func(i int) bool {
+		return f(s.items[i].obj)
+	})
+}
+
+// traverse visits the objects in the subtree in mid-order, or in reverse mid-order if
+// reverse is true, and stops once f returns false. The sentinels are skipped.
+func (s *staticSplay) traverse(root int, reverse bool, f func(int) bool) {
+	first, second := func(i int) int { return s.items[i].lchild }, func(i int) int { return s.items[i].rchild }
+	if reverse {
+		first, second = second, first
+	}
+	var stack []int
+	for i := root; i != 0 || len(stack) > 0; {
+		for ; i != 0; i = first(i) {
+			s.pushDown(i)
+			stack = append(stack, i)
+		}
+		i = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if i != s.minv && i != s.maxv && !f(i) {
 			return
 		}
-		dfs(s.items[i].lchild)
-		if i != s.minv && i != s.maxv {
-			f(s.items[i].obj)
+		i = second(i)
+	}
+}
+
+// Split costs O(log n) amortized for the tree, plus O(min(|left|, |right|)) to move the
+// smaller part into a new splay.
+func (s *staticSplay) Split(obj splay.Comparable) (splay.Splay, splay.Splay) {
+	other := New().(*staticSplay)
+	if n := s.CountLess(obj); n <= s.Len()-n {
+		// Gather the left part in the left subtree of the first object not smaller than obj.
+		s.splay(s.minv, 0)
+		var next int
+		for p := s.root; p != 0; {
+			if s.chooseChildIndex(obj, p) == 1 {
+				p = s.items[p].rchild
+			} else {
+				next = p
+				p = s.items[p].lchild
+			}
+		}
+		s.splay(next, s.minv)
+		l := s.items[next].lchild
+		s.items[next].lchild = 0
+		s.maintain(next)
+		s.maintain(s.minv)
+		other.attach(s.moveOut(l, other), other.maxv, 0)
+		return other, s
+	}
+	// Gather the right part in the right subtree of the last object smaller than obj.
+	s.splay(s.maxv, 0)
+	var prev int
+	for p := s.root; p != 0; {
+		if s.chooseChildIndex(obj, p) == 1 {
+			prev = p
+			p = s.items[p].rchild
+		} else {
+			p = s.items[p].lchild
 		}
-		dfs(s.items[i].rchild)
 	}
-	dfs(s.root)
+	s.splay(prev, s.maxv)
+	r := s.items[prev].rchild
+	s.items[prev].rchild = 0
+	s.maintain(prev)
+	s.maintain(s.maxv)
+	other.attach(s.moveOut(r, other), other.maxv, 0)
+	return s, other
 }
 
-func (s *staticSplay) ConditionRange(f splay.ConditionRangeFunc) {
-	var dfs func(int)
-	dfs = func(i int) {
-		if i == 0 {
-			return
+// Join costs O(log n) amortized for the tree, plus O(min(n, m)) to move the smaller splay
+// into the larger one.
+func (s *staticSplay) Join(other splay.Splay) bool {
+	o, ok := other.(*staticSplay)
+	if !ok || o == s {
+		return false
+	}
+	if o.Len() == 0 {
+		return true
+	}
+	if s.Len() > 0 {
+		if s.Kth(s.Len() - 1).Compare(o.Kth(0)) {
+			return false
 		}
-		dfs(s.items[i].lchild)
-		if i != s.minv && i != s.maxv {
-			if !f(s.items[i].obj) {
-				return
+		small, large := s.hash, o.hash
+		if len(small) > len(large) {
+			small, large = large, small
+		}
+		for k := range small {
+			if _, ok := large[k]; ok {
+				return false
 			}
 		}
-		dfs(s.items[i].rchild)
 	}
-	dfs(s.root)
+
+	if s.Len() < o.Len() {
+		// Move s to the left of o, and then let s take over the data of o.
+		s.splay(s.minv, 0)
+		s.splay(s.maxv, s.minv)
+		l := s.items[s.maxv].lchild
+		s.items[s.maxv].lchild = 0
+		// The min object of o has no left child once splayed to the right of minv.
+		o.splay(o.minv, 0)
+		q := o.items[o.minv].rchild
+		for ; o.items[q].lchild != 0; q = o.items[q].lchild {
+		}
+		o.splay(q, o.minv)
+		o.attach(s.moveOut(l, o), q, 0)
+		s.root, s.hash, s.items, s.infos, s.count = o.root, o.hash, o.items, o.infos, o.count
+	} else {
+		o.splay(o.minv, 0)
+		o.splay(o.maxv, o.minv)
+		r := o.items[o.maxv].lchild
+		o.items[o.maxv].lchild = 0
+		// The max object of s has no right child once splayed to the left of maxv.
+		s.splay(s.maxv, 0)
+		p := s.items[s.maxv].lchild
+		for ; s.items[p].rchild != 0; p = s.items[p].rchild {
+		}
+		s.splay(p, s.maxv)
+		s.attach(o.moveOut(r, s), p, 1)
+	}
+	o.reset()
+	return true
+}
+
+// attach sets the subtree rooted at x as the child of p, and maintains the path from p to the root.
+func (s *staticSplay) attach(x, p, dir int) {
+	if dir == 1 {
+		s.items[p].rchild = x
+	} else {
+		s.items[p].lchild = x
+	}
+	if x != 0 {
+		s.items[x].parent = p
+	}
+	for ; p != 0; p = s.items[p].parent {
+		s.maintain(p)
+	}
+}
+
+// moveOut moves the detached subtree rooted at `root`, which contains no sentinels, to the
+// end of dst and returns its new root in dst. The remaining nodes in s are compacted.
+func (s *staticSplay) moveOut(root int, dst *staticSplay) int {
+	if root == 0 {
+		return 0
+	}
+	moved := map[int]int{}
+	stack := []int{root}
+	for len(stack) > 0 {
+		i := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		dst.count++
+		moved[i] = dst.count
+		for _, c := range []int{s.items[i].lchild, s.items[i].rchild} {
+			if c != 0 {
+				stack = append(stack, c)
+			}
+		}
+	}
+	dst.items = append(dst.items, make([]node, len(moved))...)
+	dst.infos = append(dst.infos, make([]splay.MaintainInfo, len(moved))...)
+	for i, j := range moved {
+		n := s.items[i]
+		n.lchild, n.rchild, n.parent = moved[n.lchild], moved[n.rchild], moved[n.parent]
+		dst.items[j], dst.infos[j] = n, s.infos[i]
+		dst.hash[n.key] = j
+		delete(s.hash, n.key)
+	}
+
+	// Fill the holes left within the first count slots with the remaining nodes after them.
+	// Both sides have the same number of slots, so only O(len(moved)) slots are visited.
+	count := s.count - len(moved)
+	last := s.count
+	for hole := range moved {
+		if hole > count {
+			continue
+		}
+		for ; moved[last] != 0; last-- {
+		}
+		s.relocate(last, hole)
+		last--
+	}
+	for i := count + 1; i <= s.count; i++ {
+		s.items[i], s.infos[i] = node{}, nil
+	}
+	s.items, s.infos, s.count = s.items[:count+1], s.infos[:count+1], count
+	return moved[root]
+}
+
+// relocate moves the node at `from` to the unused slot `to`.
+func (s *staticSplay) relocate(from, to int) {
+	n := &s.items[from]
+	if n.parent != 0 {
+		if s.getChildIndex(from, n.parent) == 1 {
+			s.items[n.parent].rchild = to
+		} else {
+			s.items[n.parent].lchild = to
+		}
+	}
+	if n.lchild != 0 {
+		s.items[n.lchild].parent = to
+	}
+	if n.rchild != 0 {
+		s.items[n.rchild].parent = to
+	}
+	s.hash[n.key] = to
+	s.items[to] = s.items[from]
+	s.infos[to] = s.infos[from]
+	if s.root == from {
+		s.root = to
+	}
 }
 
 func (s *staticSplay) Len() int {
 	return s.count - 2
 }
 
-func (s *staticSplay) String() string {
-	output := &strings.Builder{}
-	var dfs func(int)
-	dfs = func(i int) {
-		if i == 0 {
-			return
+func (s *staticSplay) Kth(k int) splay.StoredObj {
+	if k < 0 || k >= s.Len() {
+		return nil
+	}
+	i := s.root
+	for {
+		n := &s.items[i]
+		ls, w := s.items[n.lchild].size, s.weight(i)
+		if k < ls {
+			i = n.lchild
+		} else if k < ls+w {
+			break
+		} else {
+			k -= ls + w
+			i = n.rchild
+		}
+	}
+	s.splay(i, 0)
+	return s.items[i].obj
+}
+
+func (s *staticSplay) Rank(obj splay.StoredObj) int {
+	i, ok := s.hash[obj.Key()]
+	if !ok {
+		return -1
+	}
+	s.splay(i, 0)
+	return s.items[s.items[i].lchild].size
+}
+
+func (s *staticSplay) CountLess(obj splay.Comparable) int {
+	ret, last := 0, 0
+	for i := s.root; i != 0; {
+		last = i
+		if s.chooseChildIndex(obj, i) == 1 {
+			ret += s.items[s.items[i].lchild].size + s.weight(i)
+			i = s.items[i].rchild
+		} else {
+			i = s.items[i].lchild
+		}
+	}
+	s.splay(last, 0)
+	return ret
+}
+
+func (s *staticSplay) CountInRange(lo, hi splay.Comparable) int {
+	if ret := s.CountLess(hi) - s.CountLess(lo); ret > 0 {
+		return ret
+	}
+	return 0
+}
+
+func (s *staticSplay) Min() splay.StoredObj {
+	return s.Kth(0)
+}
+
+func (s *staticSplay) Max() splay.StoredObj {
+	return s.Kth(s.Len() - 1)
+}
+
+func (s *staticSplay) LowerBound(obj splay.Comparable) splay.StoredObj {
+	_, next := s.bound(obj, false)
+	return s.result(next)
+}
+
+func (s *staticSplay) UpperBound(obj splay.Comparable) splay.StoredObj {
+	_, next := s.bound(obj, true)
+	return s.result(next)
+}
+
+func (s *staticSplay) Floor(obj splay.Comparable) splay.StoredObj {
+	prev, _ := s.bound(obj, true)
+	return s.result(prev)
+}
+
+func (s *staticSplay) Ceiling(obj splay.Comparable) splay.StoredObj {
+	return s.LowerBound(obj)
+}
+
+func (s *staticSplay) Prev(obj splay.StoredObj) splay.StoredObj {
+	i, ok := s.hash[obj.Key()]
+	if !ok {
+		return nil
+	}
+	s.splay(i, 0)
+	p := s.items[i].lchild
+	for ; s.items[p].rchild != 0; p = s.items[p].rchild {
+	}
+	return s.result(p)
+}
+
+func (s *staticSplay) Next(obj splay.StoredObj) splay.StoredObj {
+	i, ok := s.hash[obj.Key()]
+	if !ok {
+		return nil
+	}
+	s.splay(i, 0)
+	p := s.items[i].rchild
+	for ; s.items[p].lchild != 0; p = s.items[p].lchild {
+	}
+	return s.result(p)
+}
+
+// bound returns the last node before obj and the first node after obj, where the nodes
+// equal to obj are after it unless strict is true.
+func (s *staticSplay) bound(obj splay.Comparable, strict bool) (prev, next int) {
+	for p := s.root; p != 0; {
+		before := s.chooseChildIndex(obj, p) == 1
+		if strict && p != s.maxv {
+			before = p == s.minv || !s.items[p].obj.Compare(obj)
 		}
-		dfs(s.items[i].lchild)
-		if i != s.minv && i != s.maxv {
-			output.WriteString(s.items[i].key + ",")
+		if before {
+			prev, p = p, s.items[p].rchild
+		} else {
+			next, p = p, s.items[p].lchild
 		}
-		dfs(s.items[i].rchild)
 	}
-	dfs(s.root)
+	return
+}
+
+// result splays the node found by a lookup and returns its object, or nil for the sentinels.
+func (s *staticSplay) result(i int) splay.StoredObj {
+	s.splay(i, 0)
+	if i == s.minv || i == s.maxv {
+		return nil
+	}
+	return s.items[i].obj
+}
+
+func (s *staticSplay) String() string {
+	output := &strings.Builder{}
+	s.traverse(s.root, false, func(i int) bool {
+		output.WriteString(s.items[i].key + ",")
+		return true
+	})
 	return output.String()
 }
 
@@ -251,35 +742,45 @@ func (s *staticSplay) Clone() splay.Splay {
 
 func (s *staticSplay) PrintTree() string {
 	output := &strings.Builder{}
-	var dfs func(int, *strings.Builder, bool)
-	dfs = func(i int, prefixBuilder *strings.Builder, isBottom bool) {
-		prefix := prefixBuilder.String()
+	output.WriteString("SplayRoot:" + "root=" + strconv.Itoa(s.root) + "\n")
+	// Visit the right subtree, the node and then the left subtree, so that the tree diagram
+	// is printed from top to bottom.
+	type frame struct {
+		i        int
+		prefix   string
+		isBottom bool
+		visited  bool
+	}
+	stack := []frame{{i: s.root, isBottom: true}}
+	for len(stack) > 0 {
+		f := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if f.visited {
+			output.WriteString(f.prefix)
+			if f.isBottom {
+				output.WriteString("└── ")
+			} else {
+				output.WriteString("┌── ")
+			}
+			output.WriteString(s.items[f.i].obj.String() + "(" + strconv.Itoa(f.i) + ")" + "[" + s.infos[f.i].String() + "]")
+			output.WriteByte('\n')
+			continue
+		}
 		handleSon := func(j int, flag bool) {
 			if j == 0 {
 				return
 			}
-			nextPrefixBuilder := &strings.Builder{}
-			nextPrefixBuilder.WriteString(prefix)
-			if isBottom != flag {
-				nextPrefixBuilder.WriteString("│   ")
+			if f.isBottom != flag {
+				stack = append(stack, frame{i: j, prefix: f.prefix + "│   ", isBottom: flag})
 			} else {
-				nextPrefixBuilder.WriteString("    ")
+				stack = append(stack, frame{i: j, prefix: f.prefix + "    ", isBottom: flag})
 			}
-			dfs(j, nextPrefixBuilder, flag)
-		}
-		handleSon(s.items[i].rchild, false)
-		output.WriteString(prefix)
-		if isBottom {
-			output.WriteString("└── ")
-		} else {
-			output.WriteString("┌── ")
 		}
-		output.WriteString(s.items[i].obj.String() + "(" + strconv.Itoa(i) + ")" + "[" + s.infos[i].String() + "]")
-		output.WriteByte('\n')
-		handleSon(s.items[i].lchild, true)
+		handleSon(s.items[f.i].lchild, true)
+		f.visited = true
+		stack = append(stack, f)
+		handleSon(s.items[f.i].rchild, false)
 	}
-	output.WriteString("SplayRoot:" + "root=" + strconv.Itoa(s.root) + "\n")
-	dfs(s.root, &strings.Builder{}, true)
 	return output.String()
 }
 
@@ -321,7 +822,32 @@ func (s *staticSplay) rotate(x int) {
 	s.maintain(x)
 }
 
+// pushDown passes the pending tags of the node down to its children.
+func (s *staticSplay) pushDown(i int) {
+	lazy, ok := s.infos[i].(splay.LazyMaintainInfo)
+	if !ok {
+		return
+	}
+	n := &s.items[i]
+	var leftChildInfo, rightChildInfo splay.MaintainInfo
+	if n.lchild != 0 && n.lchild != s.minv {
+		leftChildInfo = s.infos[n.lchild]
+	}
+	if n.rchild != 0 && n.rchild != s.maxv {
+		rightChildInfo = s.infos[n.rchild]
+	}
+	lazy.PushDown(leftChildInfo, rightChildInfo)
+}
+
 func (s *staticSplay) splay(x, k int) {
+	// Clear the pending tags from the root to x before rotating.
+	s.path = s.path[:0]
+	for p := x; p != 0; p = s.items[p].parent {
+		s.path = append(s.path, p)
+	}
+	for i := len(s.path) - 1; i >= 0; i-- {
+		s.pushDown(s.path[i])
+	}
 	for s.items[x].parent != k {
 		y := s.items[x].parent
 		z := s.items[y].parent
@@ -0,0 +1,97 @@
+package splaytest
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/binacsgo/datastructure/splay"
+)
+
+func Test_Bound(t *testing.T) {
+	for name, backend := range backends {
+		t.Run(name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(1))
+			s := backend()
+			probe := makeObj(0, 0)
+			if s.Min() != nil || s.Max() != nil || s.LowerBound(probe) != nil || s.Floor(probe) != nil {
+				t.Errorf("Lookups on empty splay should be nil")
+			}
+			values := randomSplay(s, rng, 200)
+
+			// value returns the value of the object, or -1 for nil.
+			value := func(so splay.StoredObj) int {
+				if so == nil {
+					return -1
+				}
+				return so.(*obj).v
+			}
+			at := func(i int) int {
+				if i < 0 || i >= len(values) {
+					return -1
+				}
+				return values[i]
+			}
+			if got := value(s.Min()); got != values[0] {
+				t.Errorf("Min() = %v, want %v", got, values[0])
+			}
+			if got := value(s.Max()); got != values[len(values)-1] {
+				t.Errorf("Max() = %v, want %v", got, values[len(values)-1])
+			}
+
+			for x := -5; x <= 2005; x += 3 {
+				probe := makeObj(x, x)
+				lower := sort.SearchInts(values, x)
+				upper := sort.SearchInts(values, x+1)
+				if got := value(s.LowerBound(probe)); got != at(lower) {
+					t.Errorf("LowerBound(%v) = %v, want %v", x, got, at(lower))
+				}
+				if got := value(s.Ceiling(probe)); got != at(lower) {
+					t.Errorf("Ceiling(%v) = %v, want %v", x, got, at(lower))
+				}
+				if got := value(s.UpperBound(probe)); got != at(upper) {
+					t.Errorf("UpperBound(%v) = %v, want %v", x, got, at(upper))
+				}
+				if got := value(s.Floor(probe)); got != at(upper-1) {
+					t.Errorf("Floor(%v) = %v, want %v", x, got, at(upper-1))
+				}
+			}
+
+			for i, v := range values {
+				o := makeObj(v, v)
+				if got := value(s.Prev(o)); got != at(i-1) {
+					t.Errorf("Prev(%v) = %v, want %v", v, got, at(i-1))
+				}
+				if got := value(s.Next(o)); got != at(i+1) {
+					t.Errorf("Next(%v) = %v, want %v", v, got, at(i+1))
+				}
+			}
+			if s.Prev(makeObj(-1, -1)) != nil || s.Next(makeObj(-1, -1)) != nil {
+				t.Errorf("Prev() and Next() of missing object should be nil")
+			}
+		})
+	}
+}
+
+func Test_TreeBound(t *testing.T) {
+	for name, backend := range backends {
+		t.Run(name, func(t *testing.T) {
+			tree := splay.NewTree[int, string](backend, intCmp)
+			for _, k := range []int{10, 20, 30} {
+				tree.Put(k, "")
+			}
+			if k, _, ok := tree.Ceiling(15); !ok || k != 20 {
+				t.Errorf("Ceiling(15) = %v, %v, want %v, %v", k, ok, 20, true)
+			}
+			if k, _, ok := tree.Floor(20); !ok || k != 20 {
+				t.Errorf("Floor(20) = %v, %v, want %v, %v", k, ok, 20, true)
+			}
+			if _, _, ok := tree.Floor(5); ok {
+				t.Errorf("Floor(5) should fail")
+			}
+			if _, _, ok := tree.Ceiling(31); ok {
+				t.Errorf("Ceiling(31) should fail")
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package splaytest
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/binacsgo/datastructure/splay"
+)
+
+func Test_BuildFromSorted(t *testing.T) {
+	for name, backend := range backends {
+		t.Run(name, func(t *testing.T) {
+			s := backend()
+			s.Insert(makeObj(-1, -1))
+			if s.BuildFromSorted([]splay.StoredObj{makeObj(1, 1), makeObj(0, 0)}) ||
+				s.BuildFromSorted([]splay.StoredObj{makeObj(1, 1), makeObj(1, 2)}) {
+				t.Errorf("BuildFromSorted() should fail on unsorted input or duplicate keys")
+			}
+			checkValues(t, s, []int{-1})
+
+			values := make([]int, 1000)
+			objs := make([]splay.StoredObj, len(values))
+			for i := range values {
+				values[i] = i * 2
+				objs[i] = makeObj(values[i], values[i])
+			}
+			if !s.BuildFromSorted(objs) {
+				t.Fatalf("BuildFromSorted() should succeed")
+			}
+			if got := s.QueryPrefix(makeObj(0, 1<<30)); got == nil || got.(*info).sz != len(values) {
+				t.Errorf("QueryPrefix() = %v, want %v", got, len(values))
+			}
+			checkValues(t, s, values)
+
+			// The built splay keeps working.
+			s.Insert(makeObj(1, 1))
+			s.Delete(makeObj(0, 0))
+			values = append([]int{1}, values[1:]...)
+			checkValues(t, s, values)
+
+			if !s.BuildFromSorted(nil) || s.Len() != 0 || s.Min() != nil {
+				t.Errorf("BuildFromSorted(nil) should make the splay empty")
+			}
+			s.Insert(makeObj(1, 1))
+			checkValues(t, s, []int{1})
+		})
+	}
+}
+
+func Test_InsertBatch(t *testing.T) {
+	for name, backend := range backends {
+		t.Run(name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(1))
+			s := backend()
+			set := map[int]bool{}
+			// Both large batches which rebuild the splay and small ones which do not.
+			for _, n := range []int{100, 300, 10, 1000, 1} {
+				batch := make([]splay.StoredObj, n)
+				want := 0
+				for i := range batch {
+					v := rng.Intn(3000)
+					batch[i] = makeObj(v, v)
+					if !set[v] {
+						set[v] = true
+						want++
+					}
+				}
+				if got := s.InsertBatch(batch); got != want {
+					t.Fatalf("InsertBatch() = %v, want %v", got, want)
+				}
+
+				values := make([]int, 0, len(set))
+				for v := range set {
+					values = append(values, v)
+				}
+				sort.Ints(values)
+				checkValues(t, s, values)
+			}
+		})
+	}
+}
+
+func Benchmark_BuildFromSorted(b *testing.B) {
+	objs := make([]splay.StoredObj, 100000)
+	for i := range objs {
+		objs[i] = makeObj(i, i)
+	}
+	for name, backend := range backends {
+		b.Run(name+"/Insert", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				s := backend()
+				for _, obj := range objs {
+					s.Insert(obj)
+				}
+			}
+		})
+		b.Run(name+"/BuildFromSorted", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				backend().BuildFromSorted(objs)
+			}
+		})
+	}
+}
+
+// greedyObj claims to be greater than every object, so no order is consistent.
+type greedyObj struct{ obj }
+
+func (o *greedyObj) Compare(splay.Comparable) bool { return true }
+
+func Test_InsertBatchInconsistent(t *testing.T) {
+	for name, backend := range backends {
+		t.Run(name, func(t *testing.T) {
+			s := backend()
+			s.Insert(&greedyObj{obj{k: 0}})
+			batch := []splay.StoredObj{&greedyObj{obj{k: 1}}, &greedyObj{obj{k: 2}}, &greedyObj{obj{k: 0}}}
+			if got := s.InsertBatch(batch); got != 2 || s.Len() != 3 {
+				t.Errorf("InsertBatch() = %v, Len() = %v, want %v, %v", got, s.Len(), 2, 3)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package splaytest
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/binacsgo/datastructure/splay"
+)
+
+// sumInfo maintains the sum of the values in the subtree, and supports adding a delta to all
+// of them lazily.
+type sumInfo struct {
+	obj      *sumObj
+	cnt, sum int
+	add      int
+}
+
+var _ splay.LazyMaintainInfo = &sumInfo{}
+
+func (o *sumInfo) Maintain(ls, rs splay.MaintainInfo) {
+	o.cnt, o.sum = 1, o.obj.val
+	for _, c := range []splay.MaintainInfo{ls, rs} {
+		if c != nil {
+			o.cnt += c.(*sumInfo).cnt
+			o.sum += c.(*sumInfo).sum
+		}
+	}
+}
+
+func (o *sumInfo) Apply(tag interface{}) {
+	delta := tag.(int)
+	o.obj.val += delta
+	o.sum += delta * o.cnt
+	o.add += delta
+}
+
+func (o *sumInfo) PushDown(ls, rs splay.MaintainInfo) {
+	if o.add == 0 {
+		return
+	}
+	for _, c := range []splay.MaintainInfo{ls, rs} {
+		if c != nil {
+			c.(*sumInfo).Apply(o.add)
+		}
+	}
+	o.add = 0
+}
+
+func (o *sumInfo) Clone() splay.MaintainInfo {
+	return &sumInfo{obj: o.obj, cnt: o.cnt, sum: o.sum, add: o.add}
+}
+
+func (o *sumInfo) String() string {
+	return fmt.Sprintf("%v/%v", o.sum, o.add)
+}
+
+type sumObj struct {
+	k, val int
+}
+
+func (o *sumObj) Key() string                          { return strconv.Itoa(o.k) }
+func (o *sumObj) String() string                       { return o.Key() }
+func (o *sumObj) MakeMaintainInfo() splay.MaintainInfo { return &sumInfo{obj: o, cnt: 1, sum: o.val} }
+func (o *sumObj) Compare(so splay.Comparable) bool     { return o.k > so.(*sumObj).k }
+
+func Test_ApplyRange(t *testing.T) {
+	for name, backend := range backends {
+		t.Run(name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(1))
+			s := backend()
+			values := map[int]int{}
+			probe := func(k int) *sumObj { return &sumObj{k: k} }
+			for i := 0; i < 2000; i++ {
+				lo, hi := rng.Intn(200), rng.Intn(200)
+				switch rng.Intn(4) {
+				case 0:
+					if _, ok := values[lo]; !ok {
+						s.Insert(&sumObj{k: lo, val: hi})
+						values[lo] = hi
+					}
+				case 1:
+					s.Delete(probe(lo))
+					delete(values, lo)
+				case 2:
+					delta := rng.Intn(10)
+					want := false
+					for k := range values {
+						if lo <= k && k < hi {
+							values[k] += delta
+							want = true
+						}
+					}
+					if got := s.ApplyRange(probe(lo), probe(hi), delta); got != want {
+						t.Fatalf("ApplyRange(%v, %v) = %v, want %v", lo, hi, got, want)
+					}
+					// Get must see the tags pending on the ancestors.
+					for k, v := range values {
+						if got := s.Get(probe(k)).(*sumObj).val; got != v {
+							t.Fatalf("Get(%v) after ApplyRange(%v, %v) = %v, want %v", k, lo, hi, got, v)
+						}
+					}
+				default:
+					want := 0
+					for k, v := range values {
+						if lo <= k && k < hi {
+							want += v
+						}
+					}
+					got := 0
+					if info := s.QueryRange(probe(lo), probe(hi)); info != nil {
+						got = info.(*sumInfo).sum
+					}
+					if got != want {
+						t.Fatalf("QueryRange(%v, %v) = %v, want %v", lo, hi, got, want)
+					}
+				}
+			}
+
+			// Pending tags survive Split and Join.
+			left, right := s.Split(probe(100))
+			if !left.Join(right) {
+				t.Fatalf("Join() after Split() should succeed")
+			}
+			s = left
+
+			keys := make([]int, 0, len(values))
+			for k := range values {
+				keys = append(keys, k)
+			}
+			sort.Ints(keys)
+			i := 0
+			s.Range(func(so splay.StoredObj) {
+				if o := so.(*sumObj); o.k != keys[i] || o.val != values[o.k] {
+					t.Errorf("Range() get %v:%v, want %v:%v", o.k, o.val, keys[i], values[keys[i]])
+				}
+				i++
+			})
+		})
+	}
+}
+
+func Test_ApplyRangeWithoutLazy(t *testing.T) {
+	for name, backend := range backends {
+		t.Run(name, func(t *testing.T) {
+			s := backend()
+			s.Insert(makeObj(1, 1))
+			if s.ApplyRange(makeObj(0, 0), makeObj(2, 2), 1) {
+				t.Errorf("ApplyRange() should fail without LazyMaintainInfo")
+			}
+		})
+	}
+}
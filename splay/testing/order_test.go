@@ -0,0 +1,113 @@
+package splaytest
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/binacsgo/datastructure/splay"
+)
+
+// randomSplay fills the splay with objects of distinct values and returns the sorted values.
+func randomSplay(s splay.Splay, rng *rand.Rand, n int) []int {
+	values := map[int]bool{}
+	for len(values) < n {
+		v := rng.Intn(n * 10)
+		if values[v] {
+			continue
+		}
+		values[v] = true
+		s.Insert(makeObj(v, v))
+	}
+	// Delete some of them to exercise the maintenance after deletion.
+	for v := range values {
+		if rng.Intn(3) == 0 {
+			s.Delete(makeObj(v, v))
+			delete(values, v)
+		}
+	}
+	ret := make([]int, 0, len(values))
+	for v := range values {
+		ret = append(ret, v)
+	}
+	sort.Ints(ret)
+	return ret
+}
+
+func Test_OrderStatistics(t *testing.T) {
+	for name, backend := range backends {
+		t.Run(name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(1))
+			s := backend()
+			if s.Kth(0) != nil || s.CountLess(makeObj(0, 0)) != 0 {
+				t.Errorf("Empty splay should have no order statistics")
+			}
+			values := randomSplay(s, rng, 200)
+			if s.Len() != len(values) {
+				t.Fatalf("Len() = %v, want %v", s.Len(), len(values))
+			}
+
+			for k, v := range values {
+				if got := s.Kth(k); got == nil || got.(*obj).v != v {
+					t.Errorf("Kth(%v) = %v, want %v", k, got, v)
+				}
+				if got := s.Rank(makeObj(v, v)); got != k {
+					t.Errorf("Rank(%v) = %v, want %v", v, got, k)
+				}
+			}
+			if s.Kth(-1) != nil || s.Kth(len(values)) != nil {
+				t.Errorf("Kth() out of range should be nil")
+			}
+			if got := s.Rank(makeObj(-1, -1)); got != -1 {
+				t.Errorf("Rank() of missing object = %v, want %v", got, -1)
+			}
+
+			for i := 0; i < 100; i++ {
+				lo, hi := rng.Intn(2100)-50, rng.Intn(2100)-50
+				wantLess := sort.SearchInts(values, hi)
+				if got := s.CountLess(makeObj(hi, hi)); got != wantLess {
+					t.Errorf("CountLess(%v) = %v, want %v", hi, got, wantLess)
+				}
+				want := wantLess - sort.SearchInts(values, lo)
+				if want < 0 {
+					want = 0
+				}
+				if got := s.CountInRange(makeObj(lo, lo), makeObj(hi, hi)); got != want {
+					t.Errorf("CountInRange(%v, %v) = %v, want %v", lo, hi, got, want)
+				}
+			}
+
+			// Clone keeps the sizes.
+			o := s.Clone()
+			for k, v := range values {
+				if got := o.Kth(k); got == nil || got.(*obj).v != v {
+					t.Errorf("Clone().Kth(%v) = %v, want %v", k, got, v)
+				}
+			}
+		})
+	}
+}
+
+func Test_TreeOrderStatistics(t *testing.T) {
+	for name, backend := range backends {
+		t.Run(name, func(t *testing.T) {
+			tree := splay.NewTree[string, int](backend, strings.Compare)
+			for i, k := range []string{"d", "b", "a", "c"} {
+				tree.Put(k, i)
+			}
+			if k, v, ok := tree.Kth(1); !ok || k != "b" || v != 1 {
+				t.Errorf("Kth(1) = %v, %v, %v, want %v, %v, %v", k, v, ok, "b", 1, true)
+			}
+			if _, _, ok := tree.Kth(4); ok {
+				t.Errorf("Kth(4) should fail")
+			}
+			if got := tree.Rank("c"); got != 2 {
+				t.Errorf("Rank(c) = %v, want %v", got, 2)
+			}
+			if got := tree.Rank("bb"); got != 2 {
+				t.Errorf("Rank(bb) = %v, want %v", got, 2)
+			}
+		})
+	}
+}
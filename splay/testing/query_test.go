@@ -0,0 +1,50 @@
+package splaytest
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func Test_QueryRange(t *testing.T) {
+	for name, backend := range backends {
+		t.Run(name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(1))
+			s := backend()
+			if s.QueryPrefix(makeObj(0, 0)) != nil || s.QueryRange(makeObj(0, 0), makeObj(1, 1)) != nil {
+				t.Errorf("Query on empty splay should be nil")
+			}
+			values := randomSplay(s, rng, 200)
+
+			for i := 0; i < 200; i++ {
+				lo, hi := rng.Intn(2100)-50, rng.Intn(2100)-50
+				want := sort.SearchInts(values, hi) - sort.SearchInts(values, lo)
+				got := s.QueryRange(makeObj(lo, lo), makeObj(hi, hi))
+				if want <= 0 {
+					if got != nil {
+						t.Errorf("QueryRange(%v, %v) = %v, want nil", lo, hi, got)
+					}
+				} else if got == nil || got.(*info).sz != want {
+					t.Errorf("QueryRange(%v, %v) = %v, want %v", lo, hi, got, want)
+				}
+
+				want = sort.SearchInts(values, hi)
+				got = s.QueryPrefix(makeObj(hi, hi))
+				if want == 0 {
+					if got != nil {
+						t.Errorf("QueryPrefix(%v) = %v, want nil", hi, got)
+					}
+				} else if got == nil || got.(*info).sz != want {
+					t.Errorf("QueryPrefix(%v) = %v, want %v", hi, got, want)
+				}
+			}
+
+			// The result is a clone that does not follow later changes.
+			got := s.QueryPrefix(makeObj(3000, 3000))
+			s.Insert(makeObj(-1, -1))
+			if got.(*info).sz != len(values) {
+				t.Errorf("QueryPrefix() = %v, want %v", got, len(values))
+			}
+		})
+	}
+}
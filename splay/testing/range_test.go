@@ -0,0 +1,139 @@
+package splaytest
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/binacsgo/datastructure/splay"
+)
+
+func Test_ConditionRangeStop(t *testing.T) {
+	for name, backend := range backends {
+		t.Run(name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(1))
+			s := backend()
+			values := randomSplay(s, rng, 200)
+			for _, stop := range []int{0, 1, len(values) / 2, len(values) - 1} {
+				var got []int
+				s.ConditionRange(func(so splay.StoredObj) bool {
+					got = append(got, so.(*obj).v)
+					return len(got) <= stop
+				})
+				if len(got) != stop+1 || got[stop] != values[stop] {
+					t.Errorf("ConditionRange() visits %v objects, want %v", len(got), stop+1)
+				}
+
+				got = got[:0]
+				s.ReverseRange(func(so splay.StoredObj) bool {
+					got = append(got, so.(*obj).v)
+					return len(got) <= stop
+				})
+				if len(got) != stop+1 || got[stop] != values[len(values)-1-stop] {
+					t.Errorf("ReverseRange() visits %v objects, want %v", len(got), stop+1)
+				}
+			}
+		})
+	}
+}
+
+func Test_RangeBetween(t *testing.T) {
+	for name, backend := range backends {
+		t.Run(name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(1))
+			s := backend()
+			values := randomSplay(s, rng, 200)
+			for i := 0; i < 100; i++ {
+				lo, hi := rng.Intn(2100)-50, rng.Intn(2100)-50
+				var want []int
+				if lo < hi {
+					want = values[sort.SearchInts(values, lo):sort.SearchInts(values, hi)]
+				}
+				var got []int
+				s.RangeBetween(makeObj(lo, lo), makeObj(hi, hi), func(so splay.StoredObj) bool {
+					got = append(got, so.(*obj).v)
+					return true
+				})
+				if len(got) != len(want) {
+					t.Fatalf("RangeBetween(%v, %v) = %v, want %v", lo, hi, got, want)
+				}
+				for j := range got {
+					if got[j] != want[j] {
+						t.Fatalf("RangeBetween(%v, %v) = %v, want %v", lo, hi, got, want)
+					}
+				}
+			}
+
+			count := 0
+			s.RangeBetween(makeObj(-1, -1), makeObj(3000, 3000), func(splay.StoredObj) bool {
+				count++
+				return count < 3
+			})
+			if count != 3 {
+				t.Errorf("RangeBetween() visits %v objects, want %v", count, 3)
+			}
+		})
+	}
+}
+
+func Test_Iterator(t *testing.T) {
+	for name, backend := range backends {
+		t.Run(name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(1))
+			s := backend()
+			it := splay.NewIterator(s)
+			if it.First() || it.Last() || it.Next() || it.Valid() {
+				t.Errorf("Iterator of empty splay should not be positioned")
+			}
+			values := randomSplay(s, rng, 200)
+
+			i := 0
+			for ok := it.First(); ok; ok = it.Next() {
+				if got := it.Obj().(*obj).v; got != values[i] {
+					t.Fatalf("Obj() = %v, want %v", got, values[i])
+				}
+				i++
+			}
+			if i != len(values) || it.Obj() != nil {
+				t.Errorf("Iterator visits %v objects, want %v", i, len(values))
+			}
+			for ok := it.Last(); ok; ok = it.Prev() {
+				i--
+				if got := it.Obj().(*obj).v; got != values[i] {
+					t.Fatalf("Obj() = %v, want %v", got, values[i])
+				}
+			}
+
+			x := values[len(values)/2-1] + 1
+			if !it.Seek(makeObj(x, x)) || it.Obj().(*obj).v != values[len(values)/2] {
+				t.Errorf("Seek(%v) = %v, want %v", x, it.Obj(), values[len(values)/2])
+			}
+			// The Iterator stops once the current object is deleted.
+			s.Delete(it.Obj())
+			if it.Next() {
+				t.Errorf("Next() after deleting the current object should fail")
+			}
+		})
+	}
+}
+
+func Test_DeepTree(t *testing.T) {
+	for name, backend := range backends {
+		t.Run(name, func(t *testing.T) {
+			// Inserting in ascending order leaves a path as deep as the number of objects.
+			const n = 100000
+			s := backend()
+			for i := 0; i < n; i++ {
+				s.Insert(makeObj(i, i))
+			}
+			count := 0
+			s.Clone().Range(func(splay.StoredObj) { count++ })
+			if count != n {
+				t.Errorf("Range() visits %v objects, want %v", count, n)
+			}
+			if len(s.String()) == 0 {
+				t.Errorf("String() should not be empty")
+			}
+		})
+	}
+}
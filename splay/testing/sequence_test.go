@@ -0,0 +1,79 @@
+package splaytest
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/binacsgo/datastructure/splay/dynamic"
+)
+
+func Test_Sequence(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	q := dynamic.NewSequence[int]()
+	if _, ok := q.At(0); ok || q.InsertAt(1, 0) || q.Reverse(0, 1) {
+		t.Errorf("Operations out of range should fail")
+	}
+
+	var want []int
+	check := func() {
+		t.Helper()
+		if got := q.Slice(); fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Fatalf("Slice() = %v, want %v", got, want)
+		}
+		if q.Len() != len(want) {
+			t.Fatalf("Len() = %v, want %v", q.Len(), len(want))
+		}
+	}
+	for step := 0; step < 3000; step++ {
+		n := len(want)
+		switch rng.Intn(6) {
+		case 0, 1:
+			i, v := rng.Intn(n+1), rng.Intn(1000)
+			q.InsertAt(i, v)
+			want = append(want[:i], append([]int{v}, want[i:]...)...)
+		case 2:
+			if n == 0 {
+				continue
+			}
+			i := rng.Intn(n)
+			if v, ok := q.DeleteAt(i); !ok || v != want[i] {
+				t.Fatalf("DeleteAt(%v) = %v, %v, want %v, %v", i, v, ok, want[i], true)
+			}
+			want = append(want[:i], want[i+1:]...)
+		case 3:
+			l := rng.Intn(n + 1)
+			r := l + rng.Intn(n-l+1)
+			q.Reverse(l, r)
+			for i, j := l, r-1; i < j; i, j = i+1, j-1 {
+				want[i], want[j] = want[j], want[i]
+			}
+		case 4:
+			if n == 0 {
+				continue
+			}
+			i := rng.Intn(n)
+			if v, ok := q.At(i); !ok || v != want[i] {
+				t.Fatalf("At(%v) = %v, %v, want %v, %v", i, v, ok, want[i], true)
+			}
+		default:
+			// Split at a random position and concat the parts in a random order.
+			i := rng.Intn(n + 1)
+			tail := q.SplitAt(i)
+			if tail.Len() != n-i || q.Len() != i {
+				t.Fatalf("SplitAt(%v) get %v and %v, want %v and %v", i, q.Len(), tail.Len(), i, n-i)
+			}
+			if rng.Intn(2) == 0 {
+				q.Concat(tail)
+			} else {
+				tail.Concat(q)
+				q = tail
+				want = append(append([]int{}, want[i:]...), want[:i]...)
+			}
+		}
+		check()
+	}
+	if q.SplitAt(-1) != nil || q.SplitAt(q.Len()+1) != nil {
+		t.Errorf("SplitAt() out of range should fail")
+	}
+}
@@ -0,0 +1,123 @@
+package splaytest
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/binacsgo/datastructure/splay"
+	"github.com/binacsgo/datastructure/splay/dynamic"
+	"github.com/binacsgo/datastructure/splay/static"
+)
+
+// checkValues checks that the splay holds exactly the sorted values.
+func checkValues(t *testing.T, s splay.Splay, values []int) {
+	t.Helper()
+	if s.Len() != len(values) {
+		t.Fatalf("Len() = %v, want %v", s.Len(), len(values))
+	}
+	for k, v := range values {
+		if got := s.Kth(k); got == nil || got.(*obj).v != v {
+			t.Fatalf("Kth(%v) = %v, want %v", k, got, v)
+		}
+		if s.Get(makeObj(v, v)) == nil {
+			t.Fatalf("Get(%v) should succeed", v)
+		}
+	}
+}
+
+func Test_SplitJoin(t *testing.T) {
+	for name, backend := range backends {
+		t.Run(name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(1))
+			for _, at := range []int{-1, 0, 100, 500, 1000, 1500, 2001} {
+				s := backend()
+				values := randomSplay(s, rng, 200)
+				mid := sort.SearchInts(values, at)
+
+				left, right := s.Split(makeObj(at, at))
+				checkValues(t, left, values[:mid])
+				checkValues(t, right, values[mid:])
+				if s != left && s != right {
+					t.Errorf("Split(%v) should reuse the receiver", at)
+				}
+
+				// Both parts keep working after the nodes are moved.
+				left.Insert(makeObj(-10, -10))
+				right.Insert(makeObj(3000, 3000))
+				left.Delete(makeObj(-10, -10))
+				right.Delete(makeObj(3000, 3000))
+
+				if !splay.Join(left, right) {
+					t.Fatalf("Join() after Split(%v) should succeed", at)
+				}
+				if right.Len() != 0 || right.Kth(0) != nil {
+					t.Errorf("Join() should leave the argument empty")
+				}
+				checkValues(t, left, values)
+				right.Insert(makeObj(1, 1))
+				checkValues(t, right, []int{1})
+			}
+		})
+	}
+}
+
+func Test_JoinReject(t *testing.T) {
+	for name, backend := range backends {
+		t.Run(name, func(t *testing.T) {
+			build := func(values ...int) splay.Splay {
+				s := backend()
+				for _, v := range values {
+					s.Insert(makeObj(v, v))
+				}
+				return s
+			}
+			s := build(1, 2, 3)
+			if s.Join(build(3, 4)) || s.Join(build(0, 5)) || s.Join(s) {
+				t.Errorf("Join() should fail when the order overlaps")
+			}
+			o := build(4, 5)
+			o.Insert(makeObj(1, 6))
+			if s.Join(o) {
+				t.Errorf("Join() should fail when the keys overlap")
+			}
+			checkValues(t, s, []int{1, 2, 3})
+			if o.Len() != 3 {
+				t.Errorf("Join() should not change the argument on failure")
+			}
+
+			if !s.Join(build()) || !build().Join(s) {
+				t.Errorf("Join() with an empty splay should succeed")
+			}
+		})
+	}
+
+	if dynamic.New().Join(static.New()) || static.New().Join(dynamic.New()) {
+		t.Errorf("Join() should fail between different backends")
+	}
+}
+
+// Benchmark_SplitJoin splits off and joins back the max object, which should cost about the
+// same for every size since only the smaller part is moved.
+func Benchmark_SplitJoin(b *testing.B) {
+	for name, backend := range backends {
+		for _, n := range []int{10000, 200000} {
+			b.Run(fmt.Sprintf("%v/%v", name, n), func(b *testing.B) {
+				objs := make([]splay.StoredObj, n)
+				for i := range objs {
+					objs[i] = makeObj(i, i)
+				}
+				s := backend()
+				s.BuildFromSorted(objs)
+				probe := makeObj(n-1, n-1)
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					left, right := s.Split(probe)
+					left.Join(right)
+					s = left
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package splaytest
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/binacsgo/datastructure/splay"
+	"github.com/binacsgo/datastructure/splay/dynamic"
+	"github.com/binacsgo/datastructure/splay/static"
+)
+
+var backends = map[string]func() splay.Splay{
+	"dynamic": dynamic.New,
+	"static":  static.New,
+}
+
+func intCmp(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func Test_Tree(t *testing.T) {
+	for name, backend := range backends {
+		t.Run(name, func(t *testing.T) {
+			tree := splay.NewTree[string, int](backend, strings.Compare)
+			for i, k := range []string{"d", "b", "a", "c", "e"} {
+				if !tree.Put(k, i) {
+					t.Errorf("Put(%v) should insert a new key", k)
+				}
+			}
+			if tree.Put("a", 10) {
+				t.Errorf("Put(a) should update the existing key")
+			}
+			if v, ok := tree.Get("a"); !ok || v != 10 {
+				t.Errorf("Get(a) = %v, %v, want %v, %v", v, ok, 10, true)
+			}
+			if !tree.Delete("c") || tree.Delete("c") || tree.Has("c") {
+				t.Errorf("Delete(c) should succeed only once")
+			}
+			if tree.Len() != 4 {
+				t.Errorf("Len() = %v, want %v", tree.Len(), 4)
+			}
+
+			var keys []string
+			tree.Range(func(k string, _ int) bool {
+				keys = append(keys, k)
+				return k < "d"
+			})
+			if got := strings.Join(keys, ","); got != "a,b,d" {
+				t.Errorf("Range() = %v, want %v", got, "a,b,d")
+			}
+			if _, ok := tree.Aggregate(); ok {
+				t.Errorf("Aggregate() without aggregate function should fail")
+			}
+		})
+	}
+}
+
+func Test_AggregateTree(t *testing.T) {
+	for name, backend := range backends {
+		t.Run(name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(1))
+			tree := splay.NewAggregateTree[int, int](backend, intCmp, func(a, b int) int { return a + b })
+			if _, ok := tree.Aggregate(); ok {
+				t.Errorf("Aggregate() on empty tree should fail")
+			}
+			values := map[int]int{}
+			for i := 0; i < 300; i++ {
+				k, v := rng.Intn(100), rng.Intn(1000)
+				if rng.Intn(4) == 0 {
+					tree.Delete(k)
+					delete(values, k)
+				} else {
+					tree.Put(k, v)
+					values[k] = v
+				}
+			}
+
+			keys := make([]int, 0, len(values))
+			for k := range values {
+				keys = append(keys, k)
+			}
+			sort.Ints(keys)
+			for hi := 0; hi <= 100; hi += 7 {
+				want, ok := 0, false
+				for _, k := range keys {
+					if k < hi {
+						want, ok = want+values[k], true
+					}
+				}
+				if got, gotOk := tree.PrefixAggregate(hi); got != want || gotOk != ok {
+					t.Errorf("PrefixAggregate(%v) = %v, %v, want %v, %v", hi, got, gotOk, want, ok)
+				}
+			}
+			for lo := 0; lo <= 100; lo += 13 {
+				hi := lo + 20
+				want, ok := 0, false
+				for _, k := range keys {
+					if lo <= k && k < hi {
+						want, ok = want+values[k], true
+					}
+				}
+				if got, gotOk := tree.RangeAggregate(lo, hi); got != want || gotOk != ok {
+					t.Errorf("RangeAggregate(%v, %v) = %v, %v, want %v, %v", lo, hi, got, gotOk, want, ok)
+				}
+			}
+			if _, ok := tree.RangeAggregate(50, 50); ok {
+				t.Errorf("RangeAggregate() on empty range should fail")
+			}
+			total := 0
+			for _, v := range values {
+				total += v
+			}
+			if got, _ := tree.Aggregate(); got != total {
+				t.Errorf("Aggregate() = %v, want %v", got, total)
+			}
+		})
+	}
+}
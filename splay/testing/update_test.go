@@ -0,0 +1,62 @@
+package splaytest
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func Test_Update(t *testing.T) {
+	for name, backend := range backends {
+		t.Run(name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(1))
+			s := backend()
+			if s.Update(makeObj(1, 1)) {
+				t.Errorf("Update() of missing key should fail")
+			}
+			// The values are distinct, so that the order is unique.
+			values := map[int]int{}
+			for k := 0; k < 100; k++ {
+				values[k] = rng.Intn(1000)*100 + k
+				s.Insert(makeObj(k, values[k]))
+			}
+			if s.Insert(makeObj(0, -1)) || s.Get(makeObj(0, 0)).(*obj).v != values[0] {
+				t.Errorf("Insert() of existing key should change nothing")
+			}
+
+			for i := 0; i < 1000; i++ {
+				k := rng.Intn(100)
+				if rng.Intn(2) == 0 {
+					// Move a little, which mostly keeps the position.
+					values[k] += (rng.Intn(3) - 1) * 100
+				} else {
+					values[k] = rng.Intn(1000)*100 + k
+				}
+				if !s.Update(makeObj(k, values[k])) {
+					t.Fatalf("Update(%v) should succeed", k)
+				}
+
+				if s.Len() != len(values) {
+					t.Fatalf("Len() = %v, want %v", s.Len(), len(values))
+				}
+				if got := s.Get(makeObj(k, 0)).(*obj).v; got != values[k] {
+					t.Fatalf("Get(%v) = %v, want %v", k, got, values[k])
+				}
+			}
+
+			sorted := make([]int, 0, len(values))
+			for _, v := range values {
+				sorted = append(sorted, v)
+			}
+			sort.Ints(sorted)
+			for i, v := range sorted {
+				if got := s.Kth(i).(*obj).v; got != v {
+					t.Errorf("Kth(%v) = %v, want %v", i, got, v)
+				}
+			}
+			if got := s.QueryPrefix(makeObj(0, 1<<30)); got == nil || got.(*info).sz != len(values) {
+				t.Errorf("QueryPrefix() = %v, want %v", got, len(values))
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package splay
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Tree is a typed front end of Splay. Elements are ordered by `cmp` instead of implementing
+// StoredObj and Comparable, and an optional `aggregate` is maintained over the values of
+// every subtree.
+// The backend is created by the given constructor, e.g. `dynamic.New` or `static.New`, and
+// remains accessible through Backend() as the low-level layer.
+type Tree[K comparable, V any] struct {
+	s         Splay
+	cmp       func(a, b K) int
+	aggregate func(a, b V) V
+	entries   map[K]*treeEntry[K, V]
+	seq       uint64
+}
+
+// NewTree returns a Tree ordered by `cmp`, which returns a negative number if a < b, zero if
+// a == b and a positive number if a > b.
+func NewTree[K comparable, V any](backend func() Splay, cmp func(a, b K) int) *Tree[K, V] {
+	return NewAggregateTree[K, V](backend, cmp, nil)
+}
+
+// NewAggregateTree returns a Tree that also maintains `aggregate` over the values, which must
+// be associative, e.g. sum or max.
+func NewAggregateTree[K comparable, V any](backend func() Splay, cmp func(a, b K) int, aggregate func(a, b V) V) *Tree[K, V] {
+	return &Tree[K, V]{
+		s:         backend(),
+		cmp:       cmp,
+		aggregate: aggregate,
+		entries:   make(map[K]*treeEntry[K, V]),
+	}
+}
+
+// Put sets the value of the key. Returns true if the key is new.
+func (t *Tree[K, V]) Put(key K, val V) bool {
+	if e, ok := t.entries[key]; ok {
+		// The order of the keys does not change, so the entry is updated in place.
+		e.val = val
+		t.s.Update(e)
+		return false
+	}
+	t.seq++
+	e := &treeEntry[K, V]{
+		id:   strconv.FormatUint(t.seq, 36),
+		key:  key,
+		val:  val,
+		tree: t,
+	}
+	t.entries[key] = e
+	t.s.Insert(e)
+	return true
+}
+
+func (t *Tree[K, V]) Get(key K) (V, bool) {
+	e, ok := t.entries[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	return e.val, true
+}
+
+func (t *Tree[K, V]) Has(key K) bool {
+	_, ok := t.entries[key]
+	return ok
+}
+
+func (t *Tree[K, V]) Delete(key K) bool {
+	e, ok := t.entries[key]
+	if !ok {
+		return false
+	}
+	delete(t.entries, key)
+	return t.s.Delete(e)
+}
+
+func (t *Tree[K, V]) Len() int {
+	return t.s.Len()
+}
+
+// Range traverses the elements in ascending order of key, and stops if f returns false.
+func (t *Tree[K, V]) Range(f func(K, V) bool) {
+	t.s.ConditionRange(func(so StoredObj) bool {
+		e := so.(*treeEntry[K, V])
+		return f(e.key, e.val)
+	})
+}
+
+// Kth returns the k-th smallest key and its value, where k starts from 0.
+func (t *Tree[K, V]) Kth(k int) (K, V, bool) {
+	return t.entry(t.s.Kth(k))
+}
+
+// Rank returns the number of keys strictly less than the key, which does not need to exist.
+func (t *Tree[K, V]) Rank(key K) int {
+	return t.s.CountLess(&treeEntry[K, V]{tree: t, key: key})
+}
+
+// Floor returns the greatest key not greater than the given key and its value.
+func (t *Tree[K, V]) Floor(key K) (K, V, bool) {
+	return t.entry(t.s.Floor(&treeEntry[K, V]{tree: t, key: key}))
+}
+
+// Ceiling returns the smallest key not smaller than the given key and its value.
+func (t *Tree[K, V]) Ceiling(key K) (K, V, bool) {
+	return t.entry(t.s.Ceiling(&treeEntry[K, V]{tree: t, key: key}))
+}
+
+func (t *Tree[K, V]) entry(so StoredObj) (K, V, bool) {
+	if so == nil {
+		var key K
+		var val V
+		return key, val, false
+	}
+	e := so.(*treeEntry[K, V])
+	return e.key, e.val, true
+}
+
+// Aggregate returns the aggregate of all the values. It fails if the tree is empty or has no
+// aggregate function.
+func (t *Tree[K, V]) Aggregate() (V, bool) {
+	return t.prefixAggregate(&treeEntry[K, V]{tree: t, bound: 1})
+}
+
+// PrefixAggregate returns the aggregate of the values whose keys are strictly less than hi.
+func (t *Tree[K, V]) PrefixAggregate(hi K) (V, bool) {
+	return t.prefixAggregate(&treeEntry[K, V]{tree: t, key: hi})
+}
+
+// RangeAggregate returns the aggregate of the values whose keys are in [lo, hi).
+func (t *Tree[K, V]) RangeAggregate(lo, hi K) (V, bool) {
+	var zero V
+	if t.aggregate == nil {
+		return zero, false
+	}
+	info := t.s.QueryRange(&treeEntry[K, V]{tree: t, key: lo}, &treeEntry[K, V]{tree: t, key: hi})
+	if info == nil {
+		return zero, false
+	}
+	return info.(*treeInfo[K, V]).agg, true
+}
+
+func (t *Tree[K, V]) prefixAggregate(probe *treeEntry[K, V]) (V, bool) {
+	var zero V
+	if t.aggregate == nil {
+		return zero, false
+	}
+	info := t.s.QueryPrefix(probe)
+	if info == nil {
+		return zero, false
+	}
+	return info.(*treeInfo[K, V]).agg, true
+}
+
+// Backend returns the underlying Splay, whose elements are only meaningful to the Tree.
+func (t *Tree[K, V]) Backend() Splay {
+	return t.s
+}
+
+func (t *Tree[K, V]) String() string {
+	return t.s.String()
+}
+
+// treeEntry is the StoredObj used by Tree. The probes used for lookups are treeEntry too,
+// where a positive `bound` means greater than all the keys.
+type treeEntry[K comparable, V any] struct {
+	id    string
+	key   K
+	val   V
+	bound int
+	tree  *Tree[K, V]
+}
+
+var _ StoredObj = &treeEntry[int, int]{}
+
+func (e *treeEntry[K, V]) Key() string    { return e.id }
+func (e *treeEntry[K, V]) String() string { return fmt.Sprintf("%v:%v", e.key, e.val) }
+
+func (e *treeEntry[K, V]) MakeMaintainInfo() MaintainInfo {
+	return &treeInfo[K, V]{e: e, agg: e.val}
+}
+
+func (e *treeEntry[K, V]) Compare(c Comparable) bool {
+	if e.bound != 0 {
+		return e.bound > 0
+	}
+	return e.tree.cmp(e.key, c.(*treeEntry[K, V]).key) > 0
+}
+
+// treeInfo maintains the aggregate of the subtree.
+type treeInfo[K comparable, V any] struct {
+	e   *treeEntry[K, V]
+	agg V
+}
+
+func (o *treeInfo[K, V]) Maintain(l, r MaintainInfo) {
+	aggregate := o.e.tree.aggregate
+	if aggregate == nil {
+		return
+	}
+	o.agg = o.e.val
+	if l != nil {
+		o.agg = aggregate(l.(*treeInfo[K, V]).agg, o.agg)
+	}
+	if r != nil {
+		o.agg = aggregate(o.agg, r.(*treeInfo[K, V]).agg)
+	}
+}
+
+func (o *treeInfo[K, V]) Clone() MaintainInfo {
+	return &treeInfo[K, V]{e: o.e, agg: o.agg}
+}
+
+func (o *treeInfo[K, V]) String() string {
+	return fmt.Sprint(o.agg)
+}